@@ -0,0 +1,30 @@
+package code
+
+import (
+	"fmt"
+	"github.com/MikeMwita/go-strict/internal/baseline"
+	"github.com/MikeMwita/go-strict/models"
+	"os"
+)
+
+// applyBaseline removes the findings recorded in the baseline file from the
+// report and lists the entries that no longer match on stderr
+func applyBaseline(report *models.Report, path, baseDir string) error {
+	b, err := baseline.Load(path)
+	if err != nil {
+		return fmt.Errorf("loading baseline: %w", err)
+	}
+
+	outcome := b.Apply(report, baseDir)
+	report.Suppressed += outcome.Suppressed
+	if outcome.Grown > 0 {
+		fmt.Fprintf(os.Stderr, "Baseline: %d baselined functions grew more complex\n", outcome.Grown)
+	}
+	if len(outcome.Stale) > 0 {
+		fmt.Fprintf(os.Stderr, "Baseline: %d entries no longer match a finding and can be removed from %s:\n", len(outcome.Stale), path)
+		for _, entry := range outcome.Stale {
+			fmt.Fprintf(os.Stderr, "  %s\n", entry)
+		}
+	}
+	return nil
+}
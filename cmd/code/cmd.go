@@ -1,174 +1,313 @@
 package code
 
 import (
-	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/MikeMwita/go-strict/config"
+	"github.com/MikeMwita/go-strict/internal/baseline"
+	"github.com/MikeMwita/go-strict/internal/cache"
 	"github.com/MikeMwita/go-strict/internal/linter"
 	"github.com/MikeMwita/go-strict/models"
 	"github.com/MikeMwita/go-strict/services/complexity"
-	"github.com/MikeMwita/go-strict/utils"
+	"log"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
-var outputFormats = map[string]func([]*models.LintResult){
-	"text": printText,
-	"json": printJSON,
-	"complexity": func(results []*models.LintResult) {
-		utils.PrintDetails(results, "complexity", true)
-	},
+// Run lints the files given on the command line and exits with ExitClean,
+// ExitFindings or ExitError
+func Run() {
+	exit(run(os.Args[1:]))
 }
 
-func Run() {
+func run(arguments []string) error {
+	flags := flag.NewFlagSet("go-strict", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: go-strict [options] [files or directories]")
+		flags.PrintDefaults()
+	}
+
 	var outputFile string
-	flag.StringVar(&outputFile, "o", "", "the output file name")
-	var outputFormat string
-	flag.StringVar(&outputFormat, "f", "text", "the output format (text, json, complexity)")
+	flags.StringVar(&outputFile, "o", "", "the output file name")
+	var formats formatFlag
+	flags.Var(&formats, "f", "the output `format`, optionally followed by =path; may be repeated (default text)\n"+
+		"formats: "+strings.Join(formatNames(), ", "))
+	var htmlTemplate string
+	flags.StringVar(&htmlTemplate, "html-template", "", "the HTML template of the html format (default the embedded template)")
+	var top int
+	flags.IntVar(&top, "top", 10, "the number of offenders listed by the markdown format")
+	var baseDir string
+	flags.StringVar(&baseDir, "base", "", "the directory that reported paths are relative to (default $GITHUB_WORKSPACE or the working directory)")
+	var threshold int
+	flags.IntVar(&threshold, "threshold", 0, "report functions with a cognitive complexity above this value (default from the config, 10)")
+	var jobs int
+	flags.IntVar(&jobs, "j", 0, "the number of files analysed in parallel (default GOMAXPROCS)")
+	var cacheDir string
+	flags.StringVar(&cacheDir, "cache-dir", "", "the directory of the analysis cache (default the user cache directory)")
+	var noCache bool
+	flags.BoolVar(&noCache, "no-cache", false, "analyse every file again instead of using the cache")
+	var clearCache bool
+	flags.BoolVar(&clearCache, "clear-cache", false, "remove all cached analyses before linting")
+	var include, exclude string
+	flags.StringVar(&include, "include", "", "comma separated `globs` of the files to lint, relative to each path; ** matches any number of directories")
+	flags.StringVar(&exclude, "exclude", "", "comma separated `globs` of the files and directories to skip, e.g. **/testdata/**")
+	var skipTests bool
+	flags.BoolVar(&skipTests, "skip-tests", false, "skip _test.go files")
+	var skipVendor bool
+	flags.BoolVar(&skipVendor, "skip-vendor", false, "skip vendor directories")
+	var maxDepth int
+	flags.IntVar(&maxDepth, "max-depth", 0, "the number of directory levels walked below each path, 1 for the path only (default unrestricted)")
+	var verbose bool
+	flags.BoolVar(&verbose, "verbose", false, "print statistics about the run, such as cache hits and misses, to stderr")
+	var diffOpts diffOptions
+	flags.StringVar(&diffOpts.rev, "new-from-rev", "", "only report functions changed since this git `revision`")
+	flags.StringVar(&diffOpts.patch, "diff", "", "only report functions changed by this unified diff `file` (- for stdin)")
+	flags.BoolVar(&diffOpts.increased, "report-increased", false, "with -new-from-rev, also report changed functions whose complexity increased")
+	var writeBaseline string
+	flags.StringVar(&writeBaseline, "write-baseline", "", "write the current findings to this baseline `file`")
+	var baselinePath string
+	flags.StringVar(&baselinePath, "baseline", "", "suppress the findings recorded in this baseline `file`")
 	var configPath string
-	flag.StringVar(&configPath, "c", "config.toml", "specify the path to the configuration file")
+	flags.StringVar(&configPath, "c", "config.toml", "specify the path to the configuration file")
+	var printConfig bool
+	flags.BoolVar(&printConfig, "print-config", false, "print the effective configuration and where each value came from, then exit")
+	var failOn string
+	flags.StringVar(&failOn, "fail-on", models.SeverityWarning, "exit with status 1 on findings at or above this severity (error, warning, info, none)")
 	var showVersion bool
-	flag.BoolVar(&showVersion, "v", false, "show the version number and exit")
+	flags.BoolVar(&showVersion, "v", false, "show the version number and exit")
 	var showHelp bool
-	flag.BoolVar(&showHelp, "h", false, "show the help message and exit")
-	flag.Parse()
-	args := flag.Args()
+	flags.BoolVar(&showHelp, "h", false, "show the help message and exit")
+	if err := flags.Parse(arguments); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	args := flags.Args()
 
 	if showHelp {
-		flag.Usage()
-		os.Exit(0)
+		flags.Usage()
+		return nil
 	}
 
 	if showVersion {
-		fmt.Println("Cognitive Complexity Linter v1.0.0")
-		os.Exit(0)
+		fmt.Printf("Cognitive Complexity Linter v%s\n", toolVersion)
+		return nil
 	}
 
-	if len(args) == 0 {
-		fmt.Println("Usage: cognitive-linter [options] [files or directories]")
-		os.Exit(1)
+	failLevel, err := parseFailOn(failOn)
+	if err != nil {
+		return err
+	}
+	if diffOpts.rev != "" && diffOpts.patch != "" {
+		return errors.New("-new-from-rev and -diff cannot be used together")
+	}
+	if diffOpts.increased && diffOpts.rev == "" {
+		return errors.New("-report-increased requires -new-from-rev")
 	}
 
-	// Load configuration
-	config, err := config.LoadConfig(configPath)
+	// Load configuration: flags > env > project config > user config > defaults
+	setFlags := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+	cfg, err := config.Load(config.Options{ProjectPath: configPath, ProjectRequired: setFlags["c"]})
 	if err != nil {
-		fmt.Println("Error loading config:", err)
-		os.Exit(1)
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if setFlags["f"] {
+		cfg.Output = formats.String()
+		cfg.Sources["output"] = "flag -f"
+	}
+	if setFlags["o"] {
+		cfg.OutputFile = outputFile
+		cfg.Sources["output_file"] = "flag -o"
+	}
+	if setFlags["threshold"] {
+		cfg.Threshold = threshold
+		cfg.Sources["threshold"] = "flag -threshold"
+	}
+	if setFlags["j"] {
+		cfg.Jobs = jobs
+		cfg.Sources["jobs"] = "flag -j"
+	}
+	if setFlags["cache-dir"] {
+		cfg.CacheDir = cacheDir
+		cfg.Sources["cache_dir"] = "flag -cache-dir"
+	}
+	if setFlags["no-cache"] {
+		cfg.NoCache = noCache
+		cfg.Sources["no_cache"] = "flag -no-cache"
+	}
+	if setFlags["include"] {
+		if err := cfg.Set("include", include, "flag -include"); err != nil {
+			return err
+		}
+	}
+	if setFlags["exclude"] {
+		if err := cfg.Set("exclude", exclude, "flag -exclude"); err != nil {
+			return err
+		}
+	}
+	if setFlags["skip-tests"] {
+		cfg.SkipTests = skipTests
+		cfg.Sources["skip_tests"] = "flag -skip-tests"
+	}
+	if setFlags["skip-vendor"] {
+		cfg.SkipVendor = skipVendor
+		cfg.Sources["skip_vendor"] = "flag -skip-vendor"
+	}
+	if setFlags["max-depth"] {
+		cfg.MaxDepth = maxDepth
+		cfg.Sources["max_depth"] = "flag -max-depth"
+	}
+	if len(args) > 0 {
+		cfg.Paths = args
+		cfg.Sources["paths"] = "arguments"
+	}
+
+	if printConfig {
+		return cfg.Print(os.Stdout)
+	}
+
+	formats, err = parseFormats(cfg.Output)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cfg.Sources["output"], err)
+	}
+	if len(cfg.Paths) == 0 {
+		flags.Usage()
+		return errors.New("no files or directories given")
 	}
 
 	// Initialize complexity service and linter service
 	complexityService := complexity.NewComplexityService()
-	linter := linter.NewLinterService(config, complexityService)
+	linter := linter.NewLinterService(&cfg.LintConfig, complexityService)
 
-	// Handle output file redirection
-	var output *os.File = os.Stdout
-	if outputFile != "" {
-		output, err = os.Create(outputFile)
-		if err != nil {
-			fmt.Println("Error creating output file:", err)
-			os.Exit(1)
-		}
-		defer output.Close()
+	analysisCache, err := openCache(&cfg.LintConfig, clearCache)
+	if err != nil {
+		return err
+	}
+	if analysisCache != nil {
+		linter.SetCache(analysisCache)
 	}
 
 	// Convert the relative paths to absolute paths
 	var absArgs []string
-	for _, arg := range args {
+	for _, arg := range cfg.Paths {
 		absArg, err := filepath.Abs(arg)
 		if err != nil {
-			fmt.Println("Error resolving path:", err)
-			os.Exit(1)
+			return fmt.Errorf("resolving path: %w", err)
 		}
 		absArgs = append(absArgs, absArg)
 	}
 
-	// Lint the files
-	results, err := linter.LintFiles(absArgs)
-	if err != nil {
-		fmt.Println("Error linting files:", err)
-		os.Exit(1)
-	}
-
-	// Calculate summary statistics
-	fileCount := len(absArgs)
-	funcCount := len(results)
-	highestComplexity := 0
-	totalComplexity := 0
-	complexLineCount := 0
-
-	for _, result := range results {
-		complexity := 0
-		fmt.Sscanf(result.Message, "function has a cognitive complexity of %d", &complexity)
-		totalComplexity += complexity
-		if complexity > highestComplexity {
-			highestComplexity = complexity
-		}
-		if complexity > 12 {
-			complexLineCount++
+	if baseDir == "" {
+		baseDir = os.Getenv("GITHUB_WORKSPACE")
+	}
+	if baseDir == "" {
+		if baseDir, err = os.Getwd(); err != nil {
+			return fmt.Errorf("resolving working directory: %w", err)
 		}
 	}
 
-	avgComplexity := float64(totalComplexity) / float64(funcCount)
+	// Handle output file redirection
+	outputs, err := openOutputs(formats, cfg.OutputFile, &formatOptions{baseDir: baseDir, htmlTemplate: htmlTemplate, top: top})
+	if err != nil {
+		return err
+	}
 
-	// Print the summary
-	fmt.Fprintf(output, "Number of files: %d\n", fileCount)
-	fmt.Fprintf(output, "Number of functions: %d\n", funcCount)
-	fmt.Fprintf(output, "Highest complexity: %d\n", highestComplexity)
-	fmt.Fprintf(output, "Overall average complexity per function: %.2f\n", avgComplexity)
-	fmt.Fprintf(output, "Number of complex lines: %d\n\n", complexLineCount)
+	// Lint the files
+	report, err := linter.Analyze(absArgs)
+	if err != nil {
+		closeOutputs(outputs)
+		return fmt.Errorf("linting files: %w", err)
+	}
 
-	printResults(output, results, outputFormat)
-}
+	if writeBaseline != "" {
+		if err := baseline.New(report, baseDir).Write(writeBaseline); err != nil {
+			closeOutputs(outputs)
+			return fmt.Errorf("writing baseline: %w", err)
+		}
+	}
 
-func printResults(output *os.File, results []*models.LintResult, format string) {
-	format = strings.TrimSpace(format)
-	format = strings.ToLower(format)
+	if diffOpts.enabled() {
+		if err := applyDiff(linter, report, &diffOpts); err != nil {
+			closeOutputs(outputs)
+			return err
+		}
+	}
 
-	printFunc, ok := outputFormats[format]
-	if !ok {
-		fmt.Println("Invalid output format:", format)
-		return
+	if baselinePath != "" {
+		if err := applyBaseline(report, baselinePath, baseDir); err != nil {
+			closeOutputs(outputs)
+			return err
+		}
+	}
+
+	if verbose {
+		files, functions := linter.Counts()
+		fmt.Fprintf(os.Stderr, "Analysed %d files with %d functions\n", files, functions)
+		if analysisCache != nil {
+			hits, misses := analysisCache.Stats()
+			fmt.Fprintf(os.Stderr, "Cache: %d hits, %d misses (%s)\n", hits, misses, analysisCache.Dir())
+		} else {
+			fmt.Fprintln(os.Stderr, "Cache: disabled")
+		}
 	}
-	printFunc(results)
-}
 
-// printJSON prints the results as JSON
-func printJSON(results []*models.LintResult) {
-	data, err := json.MarshalIndent(results, "", "  ")
+	err = writeOutputs(outputs, report)
+	if closeErr := closeOutputs(outputs); err == nil {
+		err = closeErr
+	}
 	if err != nil {
-		fmt.Println("Error encoding results:", err)
-		return
+		return err
+	}
+
+	if failsOn(report.Results, failLevel) {
+		return errFindings
 	}
-	fmt.Println(string(data))
+	return nil
 }
 
-// printText prints the results in a detailed, structured format
-func printText(results []*models.LintResult) {
-	for _, result := range results {
-		fmt.Printf("%s:%d:1 - %s\n", result.File, result.Line, result.Function)
-
-		// Extract and print the details of the complexity
-		details := strings.Split(result.Message, "Complexity details:\n")
-		if len(details) > 1 {
-			lines := strings.Split(details[1], "\n")
-			for idx, line := range lines {
-				trimmedLine := strings.TrimSpace(line)
-				if trimmedLine != "" && trimmedLine != ")" {
-
-					if strings.HasPrefix(trimmedLine, "+ 1 ") {
-						trimmedLine = strings.TrimPrefix(trimmedLine, "+ 1 ")
-					}
-					if strings.HasSuffix(trimmedLine, ")") {
-						fmt.Printf("  + %d (%s)\n", idx+1, trimmedLine)
-					} else {
-						fmt.Printf("  + %d (%s)\n", idx+1, trimmedLine)
-					}
-				}
+// openCache returns the analysis cache selected by the config, or nil if caching
+// is disabled. Only an unusable explicit cache dir is an error; without a usable
+// default cache dir, e.g. in a container without $HOME, the run goes on uncached.
+func openCache(config *models.LintConfig, clearFirst bool) (*cache.Cache, error) {
+	dir := config.CacheDir
+	if dir == "" {
+		var err error
+		if dir, err = cache.DefaultDir(); err != nil {
+			if !config.NoCache {
+				log.Printf("Running without a cache: resolving cache directory: %v", err)
+			}
+			return nil, nil
+		}
+		if !config.NoCache {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				log.Printf("Running without a cache: %v", err)
+				return nil, nil
 			}
 		}
+	} else if !config.NoCache {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("opening cache directory: %w", err)
+		}
+	}
+
+	if clearFirst {
+		// clearing removes the entries of every build, so it needs no version
+		if err := cache.New(dir, "").Clear(); err != nil {
+			return nil, fmt.Errorf("clearing cache: %w", err)
+		}
+	}
+	if config.NoCache {
+		return nil, nil
+	}
 
-		fmt.Println()
+	version, err := cache.BuildVersion()
+	if err != nil {
+		log.Printf("Running without a cache: %v", err)
+		return nil, nil
 	}
+	return cache.New(dir, version), nil
 }
@@ -0,0 +1,100 @@
+package code
+
+import (
+	"errors"
+	"github.com/MikeMwita/go-strict/models"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_openCache(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "file")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		home      string
+		config    models.LintConfig
+		wantCache bool
+		wantErr   bool
+	}{
+		{name: "Test default dir", home: dir, wantCache: true},
+		{name: "Test no default dir", home: "", wantCache: false},
+		{name: "Test unusable default dir", home: blocker, wantCache: false},
+		{name: "Test explicit dir", home: "", config: models.LintConfig{CacheDir: filepath.Join(dir, "cache")}, wantCache: true},
+		{name: "Test unusable explicit dir", home: dir, config: models.LintConfig{CacheDir: filepath.Join(blocker, "cache")}, wantErr: true},
+		{name: "Test disabled", home: dir, config: models.LintConfig{NoCache: true}, wantCache: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", tt.home)
+			t.Setenv("XDG_CACHE_HOME", tt.home)
+
+			got, err := openCache(&tt.config, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("openCache() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got != nil) != tt.wantCache {
+				t.Errorf("openCache() = %v, want a cache: %v", got, tt.wantCache)
+			}
+		})
+	}
+}
+
+func TestRun_failOn(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	writeConfig := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	warnings := writeConfig("warnings.toml", "threshold = 1\n")
+	errs := writeConfig("errors.toml", "threshold = 1\nmax_complexity = 2\n")
+	broken := writeConfig("broken.toml", "threshold = \n")
+
+	const (
+		complex = "../../internal/linter/testdata/complex.go"
+		valid   = "../../internal/linter/testdata/valid.go"
+		invalid = "../../internal/linter/testdata/invalid.go"
+	)
+
+	tests := []struct {
+		name         string
+		args         []string
+		wantFindings bool
+		wantErr      bool
+	}{
+		{name: "Test warnings fail by default", args: []string{"-c", warnings, complex}, wantFindings: true},
+		{name: "Test warnings fail on error", args: []string{"-c", warnings, "-fail-on", "error", complex}},
+		{name: "Test warnings fail on warning", args: []string{"-c", warnings, "-fail-on", "warning", complex}, wantFindings: true},
+		{name: "Test warnings fail on info", args: []string{"-c", warnings, "-fail-on", "info", complex}, wantFindings: true},
+		{name: "Test warnings fail on none", args: []string{"-c", warnings, "-fail-on", "none", complex}},
+		{name: "Test errors fail on error", args: []string{"-c", errs, "-fail-on", "error", complex}, wantFindings: true},
+		{name: "Test errors fail on none", args: []string{"-c", errs, "-fail-on", "none", complex}},
+		{name: "Test clean fail on info", args: []string{"-c", warnings, "-fail-on", "INFO", valid}},
+		{name: "Test bad fail-on", args: []string{"-c", warnings, "-fail-on", "fatal", complex}, wantErr: true},
+		{name: "Test unknown flag", args: []string{"-c", warnings, "-no-such-flag", complex}, wantErr: true},
+		{name: "Test bad config", args: []string{"-c", broken, complex}, wantErr: true},
+		{name: "Test missing config", args: []string{"-c", filepath.Join(dir, "missing.toml"), complex}, wantErr: true},
+		{name: "Test parse error", args: []string{"-c", warnings, invalid}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-no-cache", "-o", filepath.Join(dir, "report.txt")}, tt.args...)
+			err := run(args)
+
+			gotFindings := errors.Is(err, errFindings)
+			gotErr := err != nil && !gotFindings
+			if gotFindings != tt.wantFindings || gotErr != tt.wantErr {
+				t.Errorf("run() error = %v, want findings %v, want other error %v", err, tt.wantFindings, tt.wantErr)
+			}
+		})
+	}
+}
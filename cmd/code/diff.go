@@ -0,0 +1,136 @@
+package code
+
+import (
+	"fmt"
+	"github.com/MikeMwita/go-strict/internal/diff"
+	"github.com/MikeMwita/go-strict/internal/linter"
+	"github.com/MikeMwita/go-strict/models"
+	"io"
+	"os"
+)
+
+// diffOptions select the changes that results are restricted to
+type diffOptions struct {
+	// rev is the base revision of --new-from-rev
+	rev string
+	// patch is the unified diff of --diff, "-" for stdin
+	patch string
+	// increased also reports touched functions whose complexity grew since rev
+	increased bool
+}
+
+func (o *diffOptions) enabled() bool {
+	return o.rev != "" || o.patch != ""
+}
+
+// loadDiff reads the diff and returns it with the directory its paths are relative to:
+// the root of the git repository, or the working directory outside of one. With a
+// revision, untracked files count as added in full.
+func loadDiff(opts *diffOptions) (*diff.Diff, string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, "", err
+	}
+	root, err := diff.Root(wd)
+	if err != nil {
+		if opts.rev != "" {
+			return nil, "", err
+		}
+		root = wd
+	}
+
+	var patch []byte
+	switch {
+	case opts.rev != "":
+		patch, err = diff.Git(root, opts.rev)
+	case opts.patch == "-":
+		patch, err = io.ReadAll(os.Stdin)
+	default:
+		patch, err = os.ReadFile(opts.patch)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("reading diff: %w", err)
+	}
+
+	d, err := diff.ParseBytes(patch)
+	if err != nil {
+		return nil, "", fmt.Errorf("parsing diff: %w", err)
+	}
+
+	// git diff leaves out files that were never added, but they are new code too
+	if opts.rev != "" {
+		untracked, err := diff.Untracked(root)
+		if err != nil {
+			return nil, "", fmt.Errorf("listing untracked files: %w", err)
+		}
+		for _, file := range untracked {
+			d.AddFile(file)
+		}
+	}
+	return d, root, nil
+}
+
+// applyDiff restricts the results of the report to the changed lines and, if
+// requested, reports the changed functions whose complexity increased since the base revision
+func applyDiff(ls *linter.LinterService, report *models.Report, opts *diffOptions) error {
+	d, root, err := loadDiff(opts)
+	if err != nil {
+		return err
+	}
+
+	report.Results = d.Filter(report.Results, root)
+	if !opts.increased {
+		return nil
+	}
+
+	reported := make(map[string]*models.LintResult)
+	for _, result := range report.Results {
+		if result.Complexity != nil {
+			reported[fmt.Sprintf("%s:%d", result.File, result.Line)] = result
+		}
+	}
+
+	for _, file := range report.Files {
+		rel := diff.RelativePath(root, file.Path)
+		fileDiff, ok := d.Files[rel]
+		if !ok || fileDiff.OldPath == "" {
+			continue
+		}
+
+		src, err := diff.Show(root, opts.rev, fileDiff.OldPath)
+		if err != nil {
+			return err
+		}
+		base, err := ls.AnalyzeSource(fileDiff.OldPath, src)
+		if err != nil {
+			return fmt.Errorf("analysing %s at %s: %w", fileDiff.OldPath, opts.rev, err)
+		}
+		baseScores := make(map[string]int)
+		for _, function := range base.Functions {
+			baseScores[function.Receiver+"."+function.Name] = function.Complexity
+		}
+
+		for _, function := range file.Functions {
+			baseScore, ok := baseScores[function.Receiver+"."+function.Name]
+			if !ok || function.Complexity <= baseScore || !d.Touches(rel, function.Line, function.EndLine) {
+				continue
+			}
+
+			message := fmt.Sprintf("cognitive complexity increased from %d to %d since %s", baseScore, function.Complexity, opts.rev)
+			if result, ok := reported[fmt.Sprintf("%s:%d", file.Path, function.Line)]; ok {
+				result.Message += " (" + message + ")"
+				continue
+			}
+			report.Results = append(report.Results, &models.LintResult{
+				File:     file.Path,
+				Line:     function.Line,
+				EndLine:  function.EndLine,
+				Message:  message,
+				Severity: models.SeverityWarning,
+				Function: function.Name,
+				Rule:     "complexity",
+			})
+		}
+	}
+	return nil
+}
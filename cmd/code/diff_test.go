@@ -0,0 +1,173 @@
+package code
+
+import (
+	"github.com/MikeMwita/go-strict/internal/linter"
+	"github.com/MikeMwita/go-strict/models"
+	"github.com/MikeMwita/go-strict/services/complexity"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+const diffBase = `package sample
+
+func Unchanged(x int) int {
+	if x > 0 {
+		if x > 1 {
+			return 2
+		}
+	}
+	return 0
+}
+
+func Grown(x int) int {
+	if x > 0 {
+		return 1
+	}
+	return 0
+}
+`
+
+const diffHead = `package sample
+
+func Unchanged(x int) int {
+	if x > 0 {
+		if x > 1 {
+			return 2
+		}
+	}
+	return 0
+}
+
+func Grown(x int) int {
+	if x > 0 {
+		for x > 10 {
+			x--
+		}
+		return 1
+	}
+	return 0
+}
+
+func Added(x int) int {
+	if x > 0 {
+		if x > 1 {
+			return 2
+		}
+	}
+	return 0
+}
+`
+
+// diffUntracked is a new file that was never added to git
+const diffUntracked = `package sample
+
+func Untracked(x int) int {
+	if x > 0 {
+		if x > 1 {
+			return 2
+		}
+	}
+	return 0
+}
+`
+
+// gitRepo creates a repository with one commit of diffBase, diffHead in the
+// working tree and the untracked file diffUntracked
+func gitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	path := filepath.Join(dir, "sample.go")
+	git("init", "-q")
+	if err := os.WriteFile(path, []byte(diffBase), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "sample.go")
+	git("commit", "-q", "-m", "base")
+	if err := os.WriteFile(path, []byte(diffHead), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "untracked.go"), []byte(diffUntracked), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func Test_applyDiff(t *testing.T) {
+	dir := gitRepo(t)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	tests := []struct {
+		name      string
+		threshold int
+		opts      diffOptions
+		want      map[string]string
+	}{
+		{
+			name:      "only changed functions",
+			threshold: 2,
+			opts:      diffOptions{rev: "HEAD"},
+			want: map[string]string{
+				"Grown":     "function has a cognitive complexity of 3 which is higher than the threshold of 2",
+				"Added":     "function has a cognitive complexity of 3 which is higher than the threshold of 2",
+				"Untracked": "function has a cognitive complexity of 3 which is higher than the threshold of 2",
+			},
+		},
+		{
+			name:      "increased functions below the threshold",
+			threshold: 10,
+			opts:      diffOptions{rev: "HEAD", increased: true},
+			want: map[string]string{
+				"Grown": "cognitive complexity increased from 1 to 3 since HEAD",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ls := linter.NewLinterService(&models.LintConfig{Threshold: tt.threshold, Rules: []string{"disable-lll"}}, complexity.NewComplexityService())
+			report, err := ls.Analyze([]string{filepath.Join(dir, "sample.go"), filepath.Join(dir, "untracked.go")})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := applyDiff(ls, report, &tt.opts); err != nil {
+				t.Fatalf("applyDiff() error = %v", err)
+			}
+
+			got := make(map[string]string)
+			for _, result := range report.Results {
+				got[result.Function] = result.Message
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("applyDiff() results = %v, want %v", got, tt.want)
+			}
+			for function, message := range tt.want {
+				if got[function] != message {
+					t.Errorf("applyDiff() %s = %q, want %q", function, got[function], message)
+				}
+			}
+		})
+	}
+}
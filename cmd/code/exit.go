@@ -0,0 +1,65 @@
+package code
+
+import (
+	"errors"
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"os"
+	"strings"
+)
+
+// Exit codes of the linter
+const (
+	// ExitClean means no findings at or above the --fail-on severity
+	ExitClean = 0
+	// ExitFindings means at least one finding at or above the --fail-on severity
+	ExitFindings = 1
+	// ExitError means the linter itself failed: bad usage, config, I/O or parse errors
+	ExitError = 2
+)
+
+// errFindings is returned by run when the results should fail the build
+var errFindings = errors.New("findings at or above the fail-on severity")
+
+// severityLevels orders the severities that --fail-on accepts, "none" never fails
+var severityLevels = map[string]int{
+	"none":                 0,
+	models.SeverityInfo:    1,
+	models.SeverityWarning: 2,
+	models.SeverityError:   3,
+}
+
+// exit reports err and terminates the process with the matching exit code
+func exit(err error) {
+	switch {
+	case err == nil:
+		os.Exit(ExitClean)
+	case errors.Is(err, errFindings):
+		os.Exit(ExitFindings)
+	default:
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(ExitError)
+	}
+}
+
+// parseFailOn validates the value of the --fail-on flag
+func parseFailOn(failOn string) (int, error) {
+	level, ok := severityLevels[strings.ToLower(strings.TrimSpace(failOn))]
+	if !ok {
+		return 0, fmt.Errorf("invalid --fail-on severity %q: expected error, warning, info or none", failOn)
+	}
+	return level, nil
+}
+
+// failsOn reports whether any result is at or above the given severity level
+func failsOn(results []*models.LintResult, level int) bool {
+	if level == 0 {
+		return false
+	}
+	for _, result := range results {
+		if severityLevels[result.Severity] >= level {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,210 @@
+package code
+
+import (
+	"errors"
+	"fmt"
+	"github.com/MikeMwita/go-strict/interfaces/presenters"
+	"github.com/MikeMwita/go-strict/models"
+	"github.com/MikeMwita/go-strict/utils"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	toolName    = "go-strict"
+	toolVersion = "1.0.0"
+	toolURI     = "https://github.com/MikeMwita/go-strict"
+)
+
+// formatOptions are the settings shared by all output formats
+type formatOptions struct {
+	// baseDir is the directory that reported paths are relative to
+	baseDir string
+	// htmlTemplate overrides the embedded template of the html format
+	htmlTemplate string
+	// top is the number of offenders listed by the markdown format
+	top int
+}
+
+var outputFormats = map[string]func(opts *formatOptions) (presenters.Formatter, error){
+	"text": func(opts *formatOptions) (presenters.Formatter, error) {
+		return &presenters.Text{}, nil
+	},
+	"json": func(opts *formatOptions) (presenters.Formatter, error) {
+		return &presenters.JSON{}, nil
+	},
+	"complexity": func(opts *formatOptions) (presenters.Formatter, error) {
+		return presenters.FormatterFunc(func(w io.Writer, report *models.Report) error {
+			presenters.WriteSummary(w, report.Summary())
+			utils.PrintDetails(w, report.Results, "complexity", true)
+			return nil
+		}), nil
+	},
+	"sarif": func(opts *formatOptions) (presenters.Formatter, error) {
+		return &presenters.SARIF{BaseDir: opts.baseDir, ToolName: toolName, ToolVersion: toolVersion, ToolURI: toolURI}, nil
+	},
+	"checkstyle": newCheckstyle,
+	"xml":        newCheckstyle,
+	"junit": func(opts *formatOptions) (presenters.Formatter, error) {
+		return &presenters.JUnit{BaseDir: opts.baseDir, ToolName: toolName}, nil
+	},
+	"github": func(opts *formatOptions) (presenters.Formatter, error) {
+		return &presenters.GitHub{BaseDir: opts.baseDir, ToolName: toolName}, nil
+	},
+	"codeclimate": func(opts *formatOptions) (presenters.Formatter, error) {
+		return &presenters.CodeClimate{BaseDir: opts.baseDir}, nil
+	},
+	"html": func(opts *formatOptions) (presenters.Formatter, error) {
+		webTemplate, err := presenters.NewWebTemplate(opts.htmlTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("parsing HTML template: %w", err)
+		}
+		webTemplate.BaseDir = opts.baseDir
+		return webTemplate, nil
+	},
+	"markdown": func(opts *formatOptions) (presenters.Formatter, error) {
+		return &presenters.Markdown{BaseDir: opts.baseDir, Top: opts.top}, nil
+	},
+}
+
+func newCheckstyle(opts *formatOptions) (presenters.Formatter, error) {
+	return &presenters.Checkstyle{BaseDir: opts.baseDir, ToolName: toolName}, nil
+}
+
+// formatNames returns the names of all output formats in alphabetical order
+func formatNames() []string {
+	names := make([]string, 0, len(outputFormats))
+	for name := range outputFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatSpec is an output format and the file it is written to; an empty path
+// means the -o file or stdout
+type formatSpec struct {
+	name string
+	path string
+}
+
+// formatFlag collects the values of repeated -f flags, each either "fmt" or "fmt=path"
+type formatFlag []formatSpec
+
+func (f *formatFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	specs := make([]string, 0, len(*f))
+	for _, spec := range *f {
+		if spec.path == "" {
+			specs = append(specs, spec.name)
+		} else {
+			specs = append(specs, spec.name+"="+spec.path)
+		}
+	}
+	return strings.Join(specs, ",")
+}
+
+func (f *formatFlag) Set(value string) error {
+	name, path, _ := strings.Cut(value, "=")
+	name = strings.ToLower(strings.TrimSpace(name))
+	if _, ok := outputFormats[name]; !ok {
+		return fmt.Errorf("invalid output format: %s (available formats: %s)", name, strings.Join(formatNames(), ", "))
+	}
+	if strings.Contains(value, "=") && strings.TrimSpace(path) == "" {
+		return fmt.Errorf("missing output file for format %s", name)
+	}
+	*f = append(*f, formatSpec{name: name, path: strings.TrimSpace(path)})
+	return nil
+}
+
+// parseFormats parses a comma separated list of output formats, e.g. "text,sarif=report.sarif"
+func parseFormats(value string) (formatFlag, error) {
+	var formats formatFlag
+	for _, spec := range strings.Split(value, ",") {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		if err := formats.Set(spec); err != nil {
+			return nil, err
+		}
+	}
+	if len(formats) == 0 {
+		formats = formatFlag{{name: "text"}}
+	}
+	return formats, nil
+}
+
+// output is a formatter bound to its destination
+type output struct {
+	spec      formatSpec
+	formatter presenters.Formatter
+	w         io.Writer
+	file      *os.File
+}
+
+// openOutputs creates the formatters and their files. Specs without a path are
+// written to outputFile, or to stdout if it is empty. The returned outputs must be closed.
+func openOutputs(specs []formatSpec, outputFile string, opts *formatOptions) ([]*output, error) {
+	var outputs []*output
+	files := make(map[string]*os.File)
+	fail := func(err error) ([]*output, error) {
+		closeOutputs(outputs)
+		return nil, err
+	}
+
+	for _, spec := range specs {
+		formatter, err := outputFormats[spec.name](opts)
+		if err != nil {
+			return fail(err)
+		}
+
+		out := &output{spec: spec, formatter: formatter, w: os.Stdout}
+		path := spec.path
+		if path == "" {
+			path = outputFile
+		}
+		if path != "" {
+			file, ok := files[path]
+			if ok && spec.path != "" {
+				return fail(fmt.Errorf("output file %s is used by more than one format", path))
+			}
+			if !ok {
+				if file, err = os.Create(path); err != nil {
+					return fail(fmt.Errorf("creating output file: %w", err))
+				}
+				files[path] = file
+				out.file = file
+			}
+			out.w = file
+		}
+		outputs = append(outputs, out)
+	}
+	return outputs, nil
+}
+
+// writeOutputs writes the report in every requested format
+func writeOutputs(outputs []*output, report *models.Report) error {
+	for _, out := range outputs {
+		if err := out.formatter.Format(out.w, report); err != nil {
+			return fmt.Errorf("writing %s output: %w", out.spec.name, err)
+		}
+	}
+	return nil
+}
+
+// closeOutputs closes the files of the outputs
+func closeOutputs(outputs []*output) error {
+	var errs []error
+	for _, out := range outputs {
+		if out.file != nil {
+			if err := out.file.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("closing output file: %w", err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
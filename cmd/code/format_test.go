@@ -0,0 +1,96 @@
+package code
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFormatFlag_Set(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    formatFlag
+		wantErr bool
+	}{
+		{
+			name:   "formats with and without a file",
+			values: []string{"text", "SARIF=report.sarif", "html = report.html"},
+			want:   formatFlag{{name: "text"}, {name: "sarif", path: "report.sarif"}, {name: "html", path: "report.html"}},
+		},
+		{
+			name:    "unknown format",
+			values:  []string{"yaml"},
+			wantErr: true,
+		},
+		{
+			name:    "missing file",
+			values:  []string{"json="},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got formatFlag
+			var err error
+			for _, value := range tt.values {
+				if err = got.Set(value); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Set() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_outputs(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("XDG_CACHE_HOME", dir)
+	configPath := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(configPath, []byte("threshold = 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	textPath := filepath.Join(dir, "report.txt")
+	jsonPath := filepath.Join(dir, "report.json")
+	sarifPath := filepath.Join(dir, "report.sarif")
+
+	err := run([]string{
+		"-c", configPath,
+		"-fail-on", "none",
+		"-o", textPath,
+		"-f", "text",
+		"-f", "json=" + jsonPath,
+		"-f", "sarif=" + sarifPath,
+		"../../internal/linter/testdata/complex.go",
+	})
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	text, err := os.ReadFile(textPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(text), "Number of files: 1\n") || !strings.Contains(string(text), "found 'range' at line: 4") {
+		t.Errorf("text output = %s", text)
+	}
+
+	for _, path := range []string{jsonPath, sarifPath} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !json.Valid(data) {
+			t.Errorf("%s is not valid JSON: %s", filepath.Base(path), data)
+		}
+	}
+}
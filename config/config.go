@@ -2,10 +2,17 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"github.com/BurntSushi/toml"
 	"github.com/MikeMwita/go-strict/models"
+	"io"
+	"io/fs"
+	"log"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -15,28 +22,229 @@ type LinterConfig struct {
 	Threshold int      `toml:"threshold"`
 }
 
-func LoadConfig(configPath string) (*models.LintConfig, error) {
-	if configPath == "" {
-		configPath = filepath.Join(".", "config.toml")
+// EnvPrefix is prepended to the upper case name of a config key to form its
+// environment variable, e.g. LINTER_THRESHOLD
+const EnvPrefix = "LINTER_"
+
+// SourceDefault is the source of values that are not set anywhere
+const SourceDefault = "default"
+
+// Defaults returns the configuration that is used when nothing else is set
+func Defaults() models.LintConfig {
+	return models.LintConfig{
+		Output:              "text",
+		Paths:               []string{"."},
+		Threshold:           10,
+		CyclomaticThreshold: 10,
+		ConstMinOccurrences: 3,
+		LineLengthMode:      "runes",
+		TabWidth:            4,
 	}
+}
+
+// Options select the layers that Load merges
+type Options struct {
+	// ProjectPath is the config file of the project, usually ./config.toml
+	ProjectPath string
+	// ProjectRequired makes a missing project config file an error, e.g. when it was given with -c
+	ProjectRequired bool
+	// UserPath is the config file of the user; empty means UserConfigPath
+	UserPath string
+	// LookupEnv reads the environment; nil means os.LookupEnv
+	LookupEnv func(key string) (string, bool)
+}
+
+// Config is the merged configuration together with the source of every value
+type Config struct {
+	models.LintConfig
+	// Sources maps a config key to where its value came from, e.g. "env LINTER_THRESHOLD"
+	Sources map[string]string
+}
 
-	if _, err := os.Stat(configPath); err == nil {
-		var config models.LintConfig
-		if _, err := toml.DecodeFile(configPath, &config); err != nil {
+// UserConfigPath returns the path of the user config file, e.g.
+// ~/.config/go-strict/config.toml, or "" if there is no user config directory
+func UserConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "go-strict", "config.toml")
+}
+
+// Load merges the defaults, the user config, the project config and the
+// environment, each overriding the ones before it. Flags are applied by the
+// caller with Set.
+func Load(opts Options) (*Config, error) {
+	cfg := &Config{LintConfig: Defaults(), Sources: make(map[string]string)}
+	for _, key := range Keys() {
+		cfg.Sources[key] = SourceDefault
+	}
+
+	userPath := opts.UserPath
+	if userPath == "" {
+		userPath = UserConfigPath()
+	}
+	if userPath != "" {
+		if err := cfg.decodeFile(userPath, "user config", false); err != nil {
 			return nil, err
 		}
-		return &config, nil
 	}
 
-	rules := os.Getenv("LINTER_RULES")
-	output := os.Getenv("LINTER_OUTPUT")
-	if rules != "" && output != "" {
-		config := models.LintConfig{
-			Rules:  strings.Split(rules, ","),
-			Output: strings.TrimSpace(output),
+	projectPath := opts.ProjectPath
+	if projectPath == "" {
+		projectPath = filepath.Join(".", "config.toml")
+	}
+	if err := cfg.decodeFile(projectPath, "project config", opts.ProjectRequired); err != nil {
+		return nil, err
+	}
+
+	lookupEnv := opts.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	for _, key := range Keys() {
+		name := EnvPrefix + strings.ToUpper(key)
+		if value, ok := lookupEnv(name); ok && strings.TrimSpace(value) != "" {
+			if err := cfg.Set(key, value, "env "+name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// LoadConfig loads the configuration with configPath as the project config
+func LoadConfig(configPath string) (*models.LintConfig, error) {
+	cfg, err := Load(Options{ProjectPath: configPath})
+	if err != nil {
+		return nil, err
+	}
+	return &cfg.LintConfig, nil
+}
+
+// decodeFile merges the keys of a TOML file into the config
+func (c *Config) decodeFile(path, layer string, required bool) error {
+	if _, err := os.Stat(path); err != nil {
+		if !required && errors.Is(err, fs.ErrNotExist) {
+			return nil
 		}
-		return &config, nil
+		return fmt.Errorf("reading %s: %w", layer, err)
 	}
 
-	return nil, errors.New("no config file or environment variables found")
+	// keys that are not in the file keep their current value
+	md, err := toml.DecodeFile(path, &c.LintConfig)
+	if err != nil {
+		return fmt.Errorf("parsing %s %s: %w", layer, path, err)
+	}
+
+	for _, key := range md.Keys() {
+		c.Sources[key.String()] = fmt.Sprintf("%s (%s)", layer, path)
+	}
+	for _, key := range md.Undecoded() {
+		log.Printf("Skipping unknown key %q in %s %s", key.String(), layer, path)
+	}
+	return nil
+}
+
+// Keys returns the config keys in alphabetical order
+func Keys() []string {
+	var keys []string
+	t := reflect.TypeOf(models.LintConfig{})
+	for i := 0; i < t.NumField(); i++ {
+		if key := t.Field(i).Tag.Get("toml"); key != "" && key != "-" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// field returns the settable field of the config key
+func (c *Config) field(key string) (reflect.Value, bool) {
+	v := reflect.ValueOf(&c.LintConfig).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("toml") == key {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// Set parses value into the config key and records its source. Lists are
+// separated by commas.
+func (c *Config) Set(key, value, source string) error {
+	field, ok := c.field(key)
+	if !ok {
+		return fmt.Errorf("unknown config key %q", key)
+	}
+
+	value = strings.TrimSpace(value)
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for %s from %s: expected an integer", value, key, source)
+		}
+		field.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for %s from %s: expected true or false", value, key, source)
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("config key %s cannot be set from %s", key, source)
+	}
+
+	c.Sources[key] = source
+	return nil
+}
+
+// Print writes the merged config as TOML, with the source of each value as a comment
+func (c *Config) Print(w io.Writer) error {
+	keys := Keys()
+	lines := make([]string, len(keys))
+	width := 0
+	for i, key := range keys {
+		field, _ := c.field(key)
+		lines[i] = key + " = " + formatValue(field)
+		if len(lines[i]) > width {
+			width = len(lines[i])
+		}
+	}
+
+	for i, key := range keys {
+		if _, err := fmt.Fprintf(w, "%-*s  # %s\n", width, lines[i], c.Sources[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatValue renders a config value as TOML
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatValue(v.Index(i))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return fmt.Sprint(v.Interface())
+	}
 }
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	user := writeConfig(t, dir, "user.toml", "threshold = 5\nmax_complexity = 30\npaths = [\"user\"]\n")
+	project := writeConfig(t, dir, "project.toml", "threshold = 8\noutput = \"json\"\npaths = [\"./cmd\", \"./internal\"]\n")
+
+	type args struct {
+		opts Options
+		env  map[string]string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantOutput  string
+		wantThresh  int
+		wantMax     int
+		wantPaths   []string
+		wantSources map[string]string
+		wantErr     bool
+	}{
+		{
+			name:       "defaults",
+			args:       args{opts: Options{ProjectPath: filepath.Join(dir, "missing.toml"), UserPath: filepath.Join(dir, "missing.toml")}},
+			wantOutput: "text",
+			wantThresh: 10,
+			wantPaths:  []string{"."},
+			wantSources: map[string]string{
+				"threshold": SourceDefault,
+				"paths":     SourceDefault,
+			},
+		},
+		{
+			name:       "project config overrides user config",
+			args:       args{opts: Options{ProjectPath: project, UserPath: user}},
+			wantOutput: "json",
+			wantThresh: 8,
+			wantMax:    30,
+			wantPaths:  []string{"./cmd", "./internal"},
+			wantSources: map[string]string{
+				"threshold":      "project config (" + project + ")",
+				"max_complexity": "user config (" + user + ")",
+				"output_file":    SourceDefault,
+			},
+		},
+		{
+			name: "env overrides config files",
+			args: args{
+				opts: Options{ProjectPath: project, UserPath: user},
+				env:  map[string]string{"LINTER_THRESHOLD": "12", "LINTER_OUTPUT": "sarif", "LINTER_PATHS": "a, b"},
+			},
+			wantOutput: "sarif",
+			wantThresh: 12,
+			wantMax:    30,
+			wantPaths:  []string{"a", "b"},
+			wantSources: map[string]string{
+				"threshold": "env LINTER_THRESHOLD",
+				"paths":     "env LINTER_PATHS",
+			},
+		},
+		{
+			name: "invalid env value",
+			args: args{
+				opts: Options{ProjectPath: project, UserPath: user},
+				env:  map[string]string{"LINTER_THRESHOLD": "high"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "missing required project config",
+			args:    args{opts: Options{ProjectPath: filepath.Join(dir, "missing.toml"), ProjectRequired: true, UserPath: user}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.args.opts
+			opts.LookupEnv = func(key string) (string, bool) {
+				value, ok := tt.args.env[key]
+				return value, ok
+			}
+
+			got, err := Load(opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Output != tt.wantOutput || got.Threshold != tt.wantThresh || got.MaxComplexity != tt.wantMax {
+				t.Errorf("Load() output = %q, threshold = %d, max_complexity = %d", got.Output, got.Threshold, got.MaxComplexity)
+			}
+			if !reflect.DeepEqual(got.Paths, tt.wantPaths) {
+				t.Errorf("Load() paths = %v, want %v", got.Paths, tt.wantPaths)
+			}
+			for key, want := range tt.wantSources {
+				if got.Sources[key] != want {
+					t.Errorf("Load() source of %s = %q, want %q", key, got.Sources[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfig_Print(t *testing.T) {
+	cfg, err := Load(Options{ProjectPath: "missing.toml", UserPath: "missing.toml", LookupEnv: func(string) (string, bool) { return "", false }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("rules", "enable-gocyclo,disable-lll", "flag -rules"); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := cfg.Print(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`output = "text"`,
+		`paths = ["."]`,
+		`rules = ["enable-gocyclo", "disable-lll"]  # flag -rules`,
+		"threshold = 10",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Print() does not contain %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -7,7 +7,7 @@ import (
 )
 
 type LintController struct {
-	linterService linter.LinterService
+	linterService *linter.LinterService
 }
 
 func (lc *LintController) LintFiles(c *gin.Context) {
@@ -53,7 +53,7 @@ func (lc *LintController) LintFunctions(c *gin.Context) {
 	c.JSON(http.StatusOK, results)
 }
 
-func NewLintController(linterService linter.LinterService) *LintController {
+func NewLintController(linterService *linter.LinterService) *LintController {
 	return &LintController{
 		linterService: linterService,
 	}
@@ -0,0 +1,90 @@
+package presenters
+
+import (
+	"encoding/xml"
+	"github.com/MikeMwita/go-strict/models"
+	"io"
+)
+
+// checkstyleVersion is the format version reported in the root element
+const checkstyleVersion = "4.3"
+
+// Checkstyle renders a report as Checkstyle XML for the Jenkins warnings
+// plugin and other Checkstyle consumers
+type Checkstyle struct {
+	// BaseDir is the directory that file names are relative to
+	BaseDir  string
+	ToolName string
+}
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// Format writes the results grouped by file, in the order the files were reported
+func (c *Checkstyle) Format(w io.Writer, report *models.Report) error {
+	out := checkstyleReport{Version: checkstyleVersion}
+	files := make(map[string]int)
+	for _, result := range report.Results {
+		name := relativePath(c.BaseDir, result.File)
+		index, ok := files[name]
+		if !ok {
+			index = len(out.Files)
+			files[name] = index
+			out.Files = append(out.Files, checkstyleFile{Name: name})
+		}
+
+		rule := result.Rule
+		if rule == "" {
+			rule = "complexity"
+		}
+		message := result.Message
+		if result.Function != "" {
+			message = result.Function + ": " + message
+		}
+
+		out.Files[index].Errors = append(out.Files[index].Errors, checkstyleError{
+			Line:     result.Line,
+			Column:   result.Column,
+			Severity: checkstyleSeverity(result.Severity),
+			Message:  message,
+			Source:   c.ToolName + "." + rule,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// checkstyleSeverity maps the severity of a result to a Checkstyle severity
+func checkstyleSeverity(severity string) string {
+	switch severity {
+	case models.SeverityError, models.SeverityInfo:
+		return severity
+	default:
+		return models.SeverityWarning
+	}
+}
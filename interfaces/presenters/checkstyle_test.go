@@ -0,0 +1,53 @@
+package presenters
+
+import (
+	"bytes"
+	"github.com/MikeMwita/go-strict/models"
+	"testing"
+)
+
+func TestCheckstyle_Format(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []*models.LintResult
+		want    string
+	}{
+		{
+			name: "no results",
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3"></checkstyle>
+`,
+		},
+		{
+			name: "grouped by file and escaped",
+			results: []*models.LintResult{
+				{File: "/src/a&b.go", Line: 3, Severity: models.SeverityError, Function: "Run", Message: `x < y && "z"`, Rule: "complexity"},
+				{File: "/src/c.go", Line: 7, Column: 81, Severity: models.SeverityInfo, Message: "too long", Rule: "lll"},
+				{File: "/src/a&b.go", Line: 12, Severity: "", Message: "untagged"},
+			},
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <file name="a&amp;b.go">
+    <error line="3" severity="error" message="Run: x &lt; y &amp;&amp; &#34;z&#34;" source="go-strict.complexity"></error>
+    <error line="12" severity="warning" message="untagged" source="go-strict.complexity"></error>
+  </file>
+  <file name="c.go">
+    <error line="7" column="81" severity="info" message="too long" source="go-strict.lll"></error>
+  </file>
+</checkstyle>
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			c := &Checkstyle{BaseDir: "/src", ToolName: "go-strict"}
+			if err := c.Format(&buf, &models.Report{Results: tt.results}); err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Format() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package presenters
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"github.com/MikeMwita/go-strict/services/complexity"
+	"io"
+)
+
+// CodeClimate renders a report as Code Climate issues, the format of the GitLab
+// Code Quality merge request widget
+type CodeClimate struct {
+	// BaseDir is the repository root that paths are relative to
+	BaseDir string
+}
+
+type codeClimateIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Content     *codeClimateContent `json:"content,omitempty"`
+	Categories  []string            `json:"categories"`
+	Location    codeClimateLocation `json:"location"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+}
+
+type codeClimateContent struct {
+	Body string `json:"body"`
+}
+
+type codeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines codeClimateLines `json:"lines"`
+}
+
+type codeClimateLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+// codeClimateCategories are the issue categories of each rule
+var codeClimateCategories = map[string][]string{
+	"goconst": {"Duplication"},
+	"lll":     {"Style"},
+}
+
+// Format writes the results as a JSON array of issues
+func (c *CodeClimate) Format(w io.Writer, report *models.Report) error {
+	issues := make([]codeClimateIssue, 0, len(report.Results))
+	seen := make(map[string]int)
+	functions := make(map[string][]*models.FunctionReport)
+	for _, file := range report.Files {
+		functions[file.Path] = append(functions[file.Path], file.Functions...)
+	}
+	for _, result := range report.Results {
+		rule := result.Rule
+		if rule == "" {
+			rule = "complexity"
+		}
+		path := relativePath(c.BaseDir, result.File)
+
+		categories, ok := codeClimateCategories[rule]
+		if !ok {
+			categories = []string{"Complexity"}
+		}
+		description := result.Message
+		if result.Function != "" {
+			description = result.Function + ": " + description
+		}
+		end := result.EndLine
+		if end < result.Line {
+			end = result.Line
+		}
+
+		// the line is left out so that an issue keeps its fingerprint when code
+		// above it moves; the receiver tells methods of the same name apart, and
+		// repeated keys, e.g. long lines, are told apart by their order
+		key := fmt.Sprintf("%s\x00%s\x00%s", path, qualifiedName(functions[result.File], result), rule)
+		seen[key]++
+		if seen[key] > 1 {
+			key = fmt.Sprintf("%s\x00%d", key, seen[key])
+		}
+		sum := md5.Sum([]byte(key))
+
+		issue := codeClimateIssue{
+			Type:        "issue",
+			CheckName:   rule,
+			Description: description,
+			Categories:  categories,
+			Location:    codeClimateLocation{Path: path, Lines: codeClimateLines{Begin: result.Line, End: end}},
+			Severity:    codeClimateSeverity(result.Severity),
+			Fingerprint: hex.EncodeToString(sum[:]),
+		}
+		if detail := complexity.GetDetail(result); detail != "" {
+			issue.Content = &codeClimateContent{Body: "```\n" + detail + "```"}
+		}
+		issues = append(issues, issue)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(issues)
+}
+
+// qualifiedName returns the name of the function of a result with its
+// receiver, e.g. "Server.Close", looked up among the functions of its file
+func qualifiedName(functions []*models.FunctionReport, result *models.LintResult) string {
+	if result.Function == "" {
+		return ""
+	}
+	for _, function := range functions {
+		if function.Name == result.Function && function.Line <= result.Line && result.Line <= function.EndLine {
+			return functionName(function)
+		}
+	}
+	return result.Function
+}
+
+// codeClimateSeverity maps the severity of a result to a Code Climate severity
+func codeClimateSeverity(severity string) string {
+	switch severity {
+	case models.SeverityError:
+		return "major"
+	case models.SeverityInfo:
+		return "info"
+	default:
+		return "minor"
+	}
+}
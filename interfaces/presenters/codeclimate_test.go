@@ -0,0 +1,91 @@
+package presenters
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/MikeMwita/go-strict/models"
+	"testing"
+)
+
+func TestCodeClimate_Format(t *testing.T) {
+	format := func(results ...*models.LintResult) []codeClimateIssue {
+		t.Helper()
+		var buf bytes.Buffer
+		c := &CodeClimate{BaseDir: "/src"}
+		if err := c.Format(&buf, &models.Report{Results: results}); err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		var issues []codeClimateIssue
+		if err := json.Unmarshal(buf.Bytes(), &issues); err != nil {
+			t.Fatalf("Format() wrote invalid JSON: %v", err)
+		}
+		return issues
+	}
+
+	serve := &models.LintResult{File: "/src/pkg/a.go", Line: 3, EndLine: 9, Function: "Serve", Rule: "complexity", Severity: models.SeverityError, Message: "too complex"}
+	moved := *serve
+	moved.Line, moved.EndLine = 13, 19
+	other := *serve
+	other.Function = "Listen"
+
+	issues := format(serve, &models.LintResult{File: "/src/pkg/a.go", Line: 5, Rule: "lll", Message: "too long"})
+	if len(issues) != 2 {
+		t.Fatalf("Format() wrote %d issues, want 2", len(issues))
+	}
+	got := issues[0]
+	if got.CheckName != "complexity" || got.Description != "Serve: too complex" || got.Severity != "major" ||
+		got.Categories[0] != "Complexity" || got.Location.Path != "pkg/a.go" || got.Location.Lines != (codeClimateLines{Begin: 3, End: 9}) {
+		t.Errorf("Format() issue = %+v", got)
+	}
+	if lll := issues[1]; lll.Severity != "minor" || lll.Categories[0] != "Style" || lll.Location.Lines != (codeClimateLines{Begin: 5, End: 5}) {
+		t.Errorf("Format() issue = %+v", lll)
+	}
+
+	if format(&moved)[0].Fingerprint != got.Fingerprint {
+		t.Errorf("Format() fingerprint changed when the function moved")
+	}
+	if format(&other)[0].Fingerprint == got.Fingerprint {
+		t.Errorf("Format() fingerprint of another function is the same")
+	}
+	repeated := format(serve, serve)
+	if repeated[0].Fingerprint == repeated[1].Fingerprint {
+		t.Errorf("Format() fingerprints of repeated issues are the same")
+	}
+}
+
+func TestCodeClimate_Format_methods(t *testing.T) {
+	fingerprints := func(results ...*models.LintResult) map[string]string {
+		t.Helper()
+		report := &models.Report{
+			Files: []*models.FileReport{{Path: "/src/pkg/a.go", Functions: []*models.FunctionReport{
+				{Name: "Close", Receiver: "A", Line: 3, EndLine: 9},
+				{Name: "Close", Receiver: "B", Line: 11, EndLine: 19},
+			}}},
+			Results: results,
+		}
+		var buf bytes.Buffer
+		if err := (&CodeClimate{BaseDir: "/src"}).Format(&buf, report); err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		var issues []codeClimateIssue
+		if err := json.Unmarshal(buf.Bytes(), &issues); err != nil {
+			t.Fatalf("Format() wrote invalid JSON: %v", err)
+		}
+		got := make(map[string]string)
+		for i, issue := range issues {
+			got[results[i].Message] = issue.Fingerprint
+		}
+		return got
+	}
+
+	closeA := &models.LintResult{File: "/src/pkg/a.go", Line: 3, EndLine: 9, Function: "Close", Rule: "complexity", Message: "A"}
+	closeB := &models.LintResult{File: "/src/pkg/a.go", Line: 11, EndLine: 19, Function: "Close", Rule: "complexity", Message: "B"}
+
+	both := fingerprints(closeA, closeB)
+	if both["A"] == both["B"] {
+		t.Errorf("Format() fingerprints of A.Close and B.Close are the same")
+	}
+	if onlyB := fingerprints(closeB); onlyB["B"] != both["B"] {
+		t.Errorf("Format() fingerprint of B.Close changed when A.Close was fixed")
+	}
+}
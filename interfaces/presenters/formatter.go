@@ -0,0 +1,19 @@
+package presenters
+
+import (
+	"github.com/MikeMwita/go-strict/models"
+	"io"
+)
+
+// Formatter writes a complete report in a single output format
+type Formatter interface {
+	Format(w io.Writer, report *models.Report) error
+}
+
+// FormatterFunc adapts a function to the Formatter interface
+type FormatterFunc func(w io.Writer, report *models.Report) error
+
+// Format calls f(w, report)
+func (f FormatterFunc) Format(w io.Writer, report *models.Report) error {
+	return f(w, report)
+}
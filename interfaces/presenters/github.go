@@ -0,0 +1,75 @@
+package presenters
+
+import (
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"github.com/MikeMwita/go-strict/services/complexity"
+	"io"
+	"strings"
+)
+
+// GitHub renders a report as GitHub Actions workflow commands, so findings are
+// shown as annotations on the lines of a pull request
+type GitHub struct {
+	// BaseDir is the workspace root that file names are relative to
+	BaseDir  string
+	ToolName string
+}
+
+var (
+	githubDataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// Format writes one ::error, ::warning or ::notice command per result
+func (g *GitHub) Format(w io.Writer, report *models.Report) error {
+	for _, result := range report.Results {
+		properties := []string{"file=" + githubPropertyEscaper.Replace(relativePath(g.BaseDir, result.File))}
+		if result.Line > 0 {
+			properties = append(properties, fmt.Sprintf("line=%d", result.Line))
+		}
+		if result.EndLine > result.Line {
+			properties = append(properties, fmt.Sprintf("endLine=%d", result.EndLine))
+		}
+		if result.Column > 0 {
+			properties = append(properties, fmt.Sprintf("col=%d", result.Column))
+		}
+		properties = append(properties, "title="+githubPropertyEscaper.Replace(g.title(result)))
+
+		message := result.Message
+		if detail := complexity.GetDetail(result); detail != "" {
+			message += "\n" + strings.TrimSuffix(detail, "\n")
+		}
+
+		_, err := fmt.Fprintf(w, "::%s %s::%s\n", githubCommand(result.Severity), strings.Join(properties, ","), githubDataEscaper.Replace(message))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// title names the tool, rule and function of a result, e.g. "go-strict (complexity): Serve"
+func (g *GitHub) title(result *models.LintResult) string {
+	rule := result.Rule
+	if rule == "" {
+		rule = "complexity"
+	}
+	title := fmt.Sprintf("%s (%s)", g.ToolName, rule)
+	if result.Function != "" {
+		title += ": " + result.Function
+	}
+	return title
+}
+
+// githubCommand maps the severity of a result to a workflow command
+func githubCommand(severity string) string {
+	switch severity {
+	case models.SeverityError:
+		return "error"
+	case models.SeverityInfo:
+		return "notice"
+	default:
+		return "warning"
+	}
+}
@@ -0,0 +1,54 @@
+package presenters
+
+import (
+	"bytes"
+	"github.com/MikeMwita/go-strict/models"
+	"testing"
+)
+
+func TestGitHub_Format(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *models.LintResult
+		want   string
+	}{
+		{
+			name:   "warning relative to the workspace",
+			result: &models.LintResult{File: "/workspace/pkg/a.go", Line: 7, Column: 81, EndLine: 7, Severity: models.SeverityWarning, Rule: "lll", Message: "line is too long"},
+			want:   "::warning file=pkg/a.go,line=7,col=81,title=go-strict (lll)::line is too long\n",
+		},
+		{
+			name:   "info is a notice",
+			result: &models.LintResult{File: "/workspace/a.go", Line: 1, Severity: models.SeverityInfo, Rule: "goconst", Message: "repeated"},
+			want:   "::notice file=a.go,line=1,title=go-strict (goconst)::repeated\n",
+		},
+		{
+			name: "error with breakdown",
+			result: &models.LintResult{
+				File:     "/workspace/a,b:c.go",
+				Line:     3,
+				EndLine:  9,
+				Severity: models.SeverityError,
+				Function: "Serve",
+				Rule:     "complexity",
+				Message:  "100% too complex",
+				Complexity: &models.ComplexityReport{
+					Increments: []models.ComplexityIncrement{{Kind: "if", Line: 4, Increment: 1, Total: 1}},
+				},
+			},
+			want: "::error file=a%2Cb%3Ac.go,line=3,endLine=9,title=go-strict (complexity)%3A Serve::100%25 too complex%0A+ 1 (found 'if' at line: 4, complexity = 1)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			g := &GitHub{BaseDir: "/workspace", ToolName: "go-strict"}
+			if err := g.Format(&buf, &models.Report{Results: []*models.LintResult{tt.result}}); err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
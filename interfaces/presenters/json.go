@@ -0,0 +1,22 @@
+package presenters
+
+import (
+	"encoding/json"
+	"github.com/MikeMwita/go-strict/models"
+	"io"
+)
+
+// JSON renders the results as an indented JSON array
+type JSON struct{}
+
+// Format writes the results as JSON
+func (j *JSON) Format(w io.Writer, report *models.Report) error {
+	results := report.Results
+	if results == nil {
+		results = []*models.LintResult{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
@@ -0,0 +1,121 @@
+package presenters
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"github.com/MikeMwita/go-strict/services/complexity"
+	"io"
+)
+
+// JUnit renders a report as a JUnit XML test report: every package is a test
+// suite and every analysed function a test case that fails when it is above
+// the complexity threshold
+type JUnit struct {
+	// BaseDir is the directory that package and file names are relative to
+	BaseDir  string
+	ToolName string
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+// functionKey identifies a function by its position
+type functionKey struct {
+	file string
+	line int
+	name string
+}
+
+// Format writes one test suite per package directory, in the order the files were analysed
+func (j *JUnit) Format(w io.Writer, report *models.Report) error {
+	failures := make(map[functionKey]*models.LintResult)
+	for _, result := range report.Results {
+		if result.Complexity != nil {
+			failures[functionKey{result.File, result.Line, result.Function}] = result
+		}
+	}
+
+	out := junitTestSuites{Name: j.ToolName}
+	suites := make(map[string]int)
+	for _, file := range report.Files {
+		name := packageName(j.BaseDir, file)
+		index, ok := suites[name]
+		if !ok {
+			index = len(out.Suites)
+			suites[name] = index
+			out.Suites = append(out.Suites, junitTestSuite{Name: name})
+		}
+		suite := &out.Suites[index]
+
+		for _, function := range file.Functions {
+			testCase := junitTestCase{
+				Name:      functionName(function),
+				ClassName: name,
+				File:      relativePath(j.BaseDir, file.Path),
+				Line:      function.Line,
+			}
+			if result, ok := failures[functionKey{file.Path, function.Line, function.Name}]; ok {
+				testCase.Failure = &junitFailure{
+					Message: result.Message,
+					Type:    result.Severity,
+					Text:    fmt.Sprintf("\n%s:%d\n%s", testCase.File, result.Line, complexity.GetDetail(result)),
+				}
+				suite.Failures++
+			}
+			suite.Cases = append(suite.Cases, testCase)
+			suite.Tests++
+		}
+
+		out.Tests += len(file.Functions)
+	}
+	for _, suite := range out.Suites {
+		out.Failures += suite.Failures
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// functionName returns the qualified name of a function, e.g. "Server.Serve"
+func functionName(function *models.FunctionReport) string {
+	if function.Receiver == "" {
+		return function.Name
+	}
+	return function.Receiver + "." + function.Name
+}
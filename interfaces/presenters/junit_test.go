@@ -0,0 +1,73 @@
+package presenters
+
+import (
+	"bytes"
+	"github.com/MikeMwita/go-strict/models"
+	"testing"
+)
+
+func TestJUnit_Format(t *testing.T) {
+	report := &models.Report{
+		Files: []*models.FileReport{
+			{
+				Path:    "/src/main.go",
+				Package: "main",
+				Functions: []*models.FunctionReport{
+					{Name: "main", Line: 3, EndLine: 5},
+				},
+			},
+			{
+				Path:    "/src/pkg/server/server.go",
+				Package: "server",
+				Functions: []*models.FunctionReport{
+					{Name: "Serve", Receiver: "Server", Line: 4, EndLine: 12, Complexity: 2},
+					{Name: "New", Line: 14, EndLine: 16},
+				},
+			},
+		},
+		Results: []*models.LintResult{
+			{
+				File:     "/src/pkg/server/server.go",
+				Line:     4,
+				Function: "Serve",
+				Severity: models.SeverityWarning,
+				Message:  "too complex",
+				Complexity: &models.ComplexityReport{
+					Score:     2,
+					Threshold: 1,
+					Increments: []models.ComplexityIncrement{
+						{Kind: "for", Line: 5, Increment: 1, Total: 1},
+						{Kind: "&&", Line: 6, Increment: 1, Nesting: 1, Total: 2},
+					},
+				},
+			},
+			{File: "/src/main.go", Line: 4, Rule: "lll", Severity: models.SeverityWarning, Message: "too long"},
+		},
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="go-strict" tests="3" failures="1">
+  <testsuite name="main" tests="1" failures="0" errors="0">
+    <testcase name="main" classname="main" file="main.go" line="3"></testcase>
+  </testsuite>
+  <testsuite name="pkg/server" tests="2" failures="1" errors="0">
+    <testcase name="Server.Serve" classname="pkg/server" file="pkg/server/server.go" line="4">
+      <failure message="too complex" type="warning"><![CDATA[
+pkg/server/server.go:4
++ 1 (found 'for' at line: 5, complexity = 1)
+  + 1 (found '&&' at line: 6, complexity = 2)
+]]></failure>
+    </testcase>
+    <testcase name="New" classname="pkg/server" file="pkg/server/server.go" line="14"></testcase>
+  </testsuite>
+</testsuites>
+`
+
+	var buf bytes.Buffer
+	j := &JUnit{BaseDir: "/src", ToolName: "go-strict"}
+	if err := j.Format(&buf, report); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("Format() = %v, want %v", got, want)
+	}
+}
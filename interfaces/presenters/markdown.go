@@ -0,0 +1,85 @@
+package presenters
+
+import (
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"io"
+	"sort"
+	"strings"
+)
+
+// defaultTop is the number of offenders listed when Top is not set
+const defaultTop = 10
+
+// Markdown renders a report as Markdown for merge request comments
+type Markdown struct {
+	// BaseDir is the directory that file names are relative to
+	BaseDir string
+	// Top is the number of offenders in the top offenders table
+	Top int
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+// Format writes a summary table, the top offenders and a collapsible breakdown
+// of every function above the threshold
+func (m *Markdown) Format(w io.Writer, report *models.Report) error {
+	var out strings.Builder
+	summary := report.Summary()
+
+	out.WriteString("## Cognitive complexity\n\n")
+	out.WriteString("| Files | Functions | Above threshold | Max complexity | Avg complexity |\n")
+	out.WriteString("|------:|----------:|----------------:|---------------:|---------------:|\n")
+	fmt.Fprintf(&out, "| %d | %d | %d | %d | %.2f |\n", summary.Files, summary.Functions, summary.Findings, summary.HighestComplexity, summary.Average)
+
+	var findings []*models.LintResult
+	for _, result := range report.Results {
+		if result.Complexity != nil {
+			findings = append(findings, result)
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Complexity.Score > findings[j].Complexity.Score
+	})
+
+	if len(findings) == 0 {
+		out.WriteString("\nNo functions above the threshold.\n")
+		_, err := io.WriteString(w, out.String())
+		return err
+	}
+
+	top := m.Top
+	if top <= 0 {
+		top = defaultTop
+	}
+	if top > len(findings) {
+		top = len(findings)
+	}
+
+	fmt.Fprintf(&out, "\n### Top %d offenders\n\n", top)
+	out.WriteString("| Function | Location | Complexity | Limit | Severity |\n")
+	out.WriteString("|----------|----------|-----------:|------:|----------|\n")
+	for _, result := range findings[:top] {
+		fmt.Fprintf(&out, "| `%s` | %s | %d | %d | %s |\n",
+			markdownEscaper.Replace(result.Function), m.location(result), result.Complexity.Score, result.Complexity.Threshold, result.Severity)
+	}
+
+	out.WriteString("\n### Breakdown\n")
+	for _, result := range findings {
+		fmt.Fprintf(&out, "\n<details>\n<summary><code>%s</code> %s: %d</summary>\n\n", result.Function, m.location(result), result.Complexity.Score)
+		out.WriteString("| Line | Increment | Nesting | Total | Found |\n")
+		out.WriteString("|-----:|----------:|--------:|------:|-------|\n")
+		for _, inc := range result.Complexity.Increments {
+			fmt.Fprintf(&out, "| %d | +%d | %d | %d | `%s` |\n", inc.Line, inc.Increment, inc.Nesting, inc.Total, markdownEscaper.Replace(inc.Kind))
+		}
+		out.WriteString("\n</details>\n")
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// location returns the file and line of a result relative to BaseDir
+func (m *Markdown) location(result *models.LintResult) string {
+	return fmt.Sprintf("%s:%d", markdownEscaper.Replace(relativePath(m.BaseDir, result.File)), result.Line)
+}
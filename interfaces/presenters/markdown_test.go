@@ -0,0 +1,84 @@
+package presenters
+
+import (
+	"bytes"
+	"github.com/MikeMwita/go-strict/models"
+	"testing"
+)
+
+func TestMarkdown_Format(t *testing.T) {
+	finding := func(function string, line, score int) *models.LintResult {
+		return &models.LintResult{
+			File:     "/src/pkg/a.go",
+			Line:     line,
+			Function: function,
+			Severity: models.SeverityWarning,
+			Complexity: &models.ComplexityReport{
+				Score:      score,
+				Threshold:  1,
+				Increments: []models.ComplexityIncrement{{Kind: "||", Line: line + 1, Increment: score, Total: score}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		top    int
+		report *models.Report
+		want   string
+	}{
+		{
+			name:   "no findings",
+			report: &models.Report{},
+			want: `## Cognitive complexity
+
+| Files | Functions | Above threshold | Max complexity | Avg complexity |
+|------:|----------:|----------------:|---------------:|---------------:|
+| 0 | 0 | 0 | 0 | 0.00 |
+
+No functions above the threshold.
+`,
+		},
+		{
+			name: "top offenders sorted by score",
+			top:  1,
+			report: &models.Report{
+				Files: []*models.FileReport{{Path: "/src/pkg/a.go", Functions: []*models.FunctionReport{
+					{Name: "Low", Complexity: 2}, {Name: "High", Complexity: 3},
+				}}},
+				Results: []*models.LintResult{finding("Low", 3, 2), finding("High", 9, 3)},
+			},
+			want: "## Cognitive complexity\n\n" +
+				"| Files | Functions | Above threshold | Max complexity | Avg complexity |\n" +
+				"|------:|----------:|----------------:|---------------:|---------------:|\n" +
+				"| 1 | 2 | 2 | 3 | 2.50 |\n" +
+				"\n### Top 1 offenders\n\n" +
+				"| Function | Location | Complexity | Limit | Severity |\n" +
+				"|----------|----------|-----------:|------:|----------|\n" +
+				"| `High` | pkg/a.go:9 | 3 | 1 | warning |\n" +
+				"\n### Breakdown\n" +
+				"\n<details>\n<summary><code>High</code> pkg/a.go:9: 3</summary>\n\n" +
+				"| Line | Increment | Nesting | Total | Found |\n" +
+				"|-----:|----------:|--------:|------:|-------|\n" +
+				"| 10 | +3 | 0 | 3 | `\\|\\|` |\n" +
+				"\n</details>\n" +
+				"\n<details>\n<summary><code>Low</code> pkg/a.go:3: 2</summary>\n\n" +
+				"| Line | Increment | Nesting | Total | Found |\n" +
+				"|-----:|----------:|--------:|------:|-------|\n" +
+				"| 4 | +2 | 0 | 2 | `\\|\\|` |\n" +
+				"\n</details>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			m := &Markdown{BaseDir: "/src", Top: tt.top}
+			if err := m.Format(&buf, tt.report); err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Format() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
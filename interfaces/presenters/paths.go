@@ -0,0 +1,41 @@
+package presenters
+
+import (
+	"github.com/MikeMwita/go-strict/models"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// relativePath returns path relative to base using forward slashes. Paths
+// outside of base, or any path when base is empty, are returned unchanged.
+func relativePath(base, file string) string {
+	if base == "" {
+		return filepath.ToSlash(file)
+	}
+
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+
+	rel, err := filepath.Rel(absBase, absFile)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// packageName returns the directory of the file relative to base, or the
+// package name for files directly in base
+func packageName(base string, file *models.FileReport) string {
+	dir := path.Dir(relativePath(base, file.Path))
+	if dir == "." || dir == "/" {
+		return file.Package
+	}
+	return dir
+}
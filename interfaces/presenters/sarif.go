@@ -0,0 +1,237 @@
+package presenters
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifSrcRoot is the uriBaseId that result locations are relative to
+	sarifSrcRoot = "%SRCROOT%"
+)
+
+// SARIF renders a report as a SARIF 2.1.0 log for code scanning dashboards
+type SARIF struct {
+	// BaseDir is the directory that artifact URIs are relative to
+	BaseDir     string
+	ToolName    string
+	ToolVersion string
+	ToolURI     string
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string                `json:"name"`
+	Version        string                `json:"version,omitempty"`
+	InformationURI string                `json:"informationUri,omitempty"`
+	Rules          []sarifRuleDescriptor `json:"rules"`
+}
+
+type sarifRuleDescriptor struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID           string          `json:"ruleId"`
+	RuleIndex        int             `json:"ruleIndex"`
+	Level            string          `json:"level"`
+	Message          sarifMessage    `json:"message"`
+	Locations        []sarifLocation `json:"locations"`
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+}
+
+type sarifLocation struct {
+	ID               *int                   `json:"id,omitempty"`
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+	Message          *sarifMessage          `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// sarifLevel maps the severity of a result to a SARIF level
+func sarifLevel(severity string) string {
+	switch severity {
+	case models.SeverityError:
+		return "error"
+	case models.SeverityInfo:
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+// Format writes the report as an indented SARIF log
+func (s *SARIF) Format(w io.Writer, report *models.Report) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           s.ToolName,
+			Version:        s.ToolVersion,
+			InformationURI: s.ToolURI,
+			Rules:          []sarifRuleDescriptor{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	if s.BaseDir != "" {
+		baseURI, err := fileURI(s.BaseDir, true)
+		if err != nil {
+			return err
+		}
+		run.OriginalURIBaseIDs = map[string]sarifArtifactLocation{sarifSrcRoot: {URI: baseURI}}
+	}
+
+	ruleIndex := make(map[string]int)
+	addRule := func(id, description string) int {
+		if index, ok := ruleIndex[id]; ok {
+			return index
+		}
+		if description == "" {
+			description = id
+		}
+		ruleIndex[id] = len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRuleDescriptor{
+			ID:                   id,
+			ShortDescription:     sarifMessage{Text: description},
+			DefaultConfiguration: sarifConfiguration{Level: "warning"},
+		})
+		return ruleIndex[id]
+	}
+	for _, rule := range report.Rules {
+		addRule(rule.ID, rule.Description)
+	}
+
+	for _, result := range report.Results {
+		ruleID := result.Rule
+		if ruleID == "" {
+			ruleID = "complexity"
+		}
+
+		location := s.location(result.File, result.Line, result.Column, result.EndLine)
+		if result.Function != "" {
+			location.LogicalLocations = []sarifLogicalLocation{{Name: result.Function, Kind: "function"}}
+		}
+
+		sr := sarifResult{
+			RuleID:    ruleID,
+			RuleIndex: addRule(ruleID, ""),
+			Level:     sarifLevel(result.Severity),
+			Message:   sarifMessage{Text: result.Message},
+			Locations: []sarifLocation{location},
+		}
+
+		if result.Complexity != nil {
+			for i, inc := range result.Complexity.Increments {
+				id := i + 1
+				related := s.location(result.File, inc.Line, inc.Column, 0)
+				related.ID = &id
+				related.Message = &sarifMessage{Text: fmt.Sprintf("+%d (%s, nesting %d)", inc.Increment, inc.Kind, inc.Nesting)}
+				sr.RelatedLocations = append(sr.RelatedLocations, related)
+			}
+		}
+
+		run.Results = append(run.Results, sr)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	})
+}
+
+// location builds the physical location of a file region. Files below BaseDir
+// are relative to %SRCROOT%, other files use an absolute file URI.
+func (s *SARIF) location(file string, line, column, endLine int) sarifLocation {
+	artifact := sarifArtifactLocation{URI: filepath.ToSlash(file)}
+	rel := relativePath(s.BaseDir, file)
+	if s.BaseDir != "" && rel != filepath.ToSlash(file) {
+		artifact = sarifArtifactLocation{URI: (&url.URL{Path: rel}).String(), URIBaseID: sarifSrcRoot}
+	} else if uri, err := fileURI(file, false); err == nil {
+		artifact.URI = uri
+	}
+
+	if line < 1 {
+		line = 1
+	}
+	if endLine < line {
+		endLine = 0
+	}
+	return sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: artifact,
+			Region:           sarifRegion{StartLine: line, StartColumn: column, EndLine: endLine},
+		},
+	}
+}
+
+// fileURI returns the absolute file:// URI of a path. The URI of a directory
+// ends with a slash, as SARIF requires for the base URIs of a run.
+func fileURI(path string, dir bool) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	abs = filepath.ToSlash(abs)
+	if !strings.HasPrefix(abs, "/") {
+		// windows drive letters
+		abs = "/" + abs
+	}
+	if dir && !strings.HasSuffix(abs, "/") {
+		abs += "/"
+	}
+	return (&url.URL{Scheme: "file", Path: abs}).String(), nil
+}
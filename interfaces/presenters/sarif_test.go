@@ -0,0 +1,156 @@
+package presenters
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/MikeMwita/go-strict/models"
+	"reflect"
+	"testing"
+)
+
+func TestSARIF_Format(t *testing.T) {
+	report := &models.Report{
+		Rules: []models.RuleInfo{
+			{ID: "complexity", Description: "Functions should not be too complex"},
+			{ID: "lll", Description: "Lines should not be too long"},
+		},
+		Results: []*models.LintResult{
+			{
+				File:     "/src/project/pkg/a.go",
+				Line:     3,
+				EndLine:  9,
+				Message:  "too complex",
+				Severity: models.SeverityError,
+				Function: "Classify",
+				Rule:     "complexity",
+				Complexity: &models.ComplexityReport{
+					Score:     2,
+					Threshold: 1,
+					Increments: []models.ComplexityIncrement{
+						{Kind: "if", Line: 4, Column: 2, Increment: 1, Total: 1},
+						{Kind: "else", Line: 6, Column: 4, Increment: 1, Total: 2},
+					},
+				},
+			},
+			{File: "/elsewhere/b.go", Line: 7, Column: 81, EndLine: 7, Message: "too long", Severity: models.SeverityInfo, Rule: "lll"},
+		},
+	}
+
+	var buf bytes.Buffer
+	sarif := &SARIF{BaseDir: "/src/project", ToolName: "go-strict", ToolVersion: "1.0.0"}
+	if err := sarif.Format(&buf, report); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var got sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Format() wrote invalid JSON: %v", err)
+	}
+	if got.Version != sarifVersion || len(got.Runs) != 1 {
+		t.Fatalf("Format() version = %q, runs = %d", got.Version, len(got.Runs))
+	}
+
+	run := got.Runs[0]
+	if run.Tool.Driver.Name != "go-strict" || len(run.Tool.Driver.Rules) != 2 {
+		t.Errorf("Format() driver = %+v", run.Tool.Driver)
+	}
+	if base := run.OriginalURIBaseIDs[sarifSrcRoot].URI; base != "file:///src/project/" {
+		t.Errorf("Format() %s = %q, want %q", sarifSrcRoot, base, "file:///src/project/")
+	}
+
+	tests := []struct {
+		name      string
+		ruleIndex int
+		level     string
+		artifact  sarifArtifactLocation
+		region    sarifRegion
+		related   int
+	}{
+		{
+			name:      "relative to the base directory",
+			ruleIndex: 0,
+			level:     "error",
+			artifact:  sarifArtifactLocation{URI: "pkg/a.go", URIBaseID: sarifSrcRoot},
+			region:    sarifRegion{StartLine: 3, EndLine: 9},
+			related:   2,
+		},
+		{
+			name:      "outside of the base directory",
+			ruleIndex: 1,
+			level:     "note",
+			artifact:  sarifArtifactLocation{URI: "file:///elsewhere/b.go"},
+			region:    sarifRegion{StartLine: 7, StartColumn: 81, EndLine: 7},
+		},
+	}
+	if len(run.Results) != len(tests) {
+		t.Fatalf("Format() wrote %d results, want %d", len(run.Results), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := run.Results[i]
+			if result.RuleIndex != tt.ruleIndex || result.Level != tt.level {
+				t.Errorf("ruleIndex = %d, level = %q, want %d, %q", result.RuleIndex, result.Level, tt.ruleIndex, tt.level)
+			}
+			location := result.Locations[0].PhysicalLocation
+			if !reflect.DeepEqual(location.ArtifactLocation, tt.artifact) {
+				t.Errorf("artifactLocation = %+v, want %+v", location.ArtifactLocation, tt.artifact)
+			}
+			if !reflect.DeepEqual(location.Region, tt.region) {
+				t.Errorf("region = %+v, want %+v", location.Region, tt.region)
+			}
+			if len(result.RelatedLocations) != tt.related {
+				t.Errorf("relatedLocations = %d, want %d", len(result.RelatedLocations), tt.related)
+			}
+		})
+	}
+}
+
+func TestSARIF_Format_dottedBase(t *testing.T) {
+	report := &models.Report{Results: []*models.LintResult{
+		{File: "/tmp/proj.v2/pkg/a.go", Line: 3, Message: "too complex", Severity: models.SeverityWarning, Rule: "complexity"},
+	}}
+
+	var buf bytes.Buffer
+	if err := (&SARIF{BaseDir: "/tmp/proj.v2", ToolName: "go-strict"}).Format(&buf, report); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	var got sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Format() wrote invalid JSON: %v", err)
+	}
+
+	run := got.Runs[0]
+	if base := run.OriginalURIBaseIDs[sarifSrcRoot].URI; base != "file:///tmp/proj.v2/" {
+		t.Errorf("Format() %s = %q, want %q", sarifSrcRoot, base, "file:///tmp/proj.v2/")
+	}
+	want := sarifArtifactLocation{URI: "pkg/a.go", URIBaseID: sarifSrcRoot}
+	if got := run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation; !reflect.DeepEqual(got, want) {
+		t.Errorf("artifactLocation = %+v, want %+v", got, want)
+	}
+}
+
+func Test_fileURI(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		dir  bool
+		want string
+	}{
+		{name: "directory", path: "/src/project", dir: true, want: "file:///src/project/"},
+		{name: "dotted directory", path: "/tmp/proj.v2", dir: true, want: "file:///tmp/proj.v2/"},
+		{name: "root directory", path: "/", dir: true, want: "file:///"},
+		{name: "file", path: "/src/project/a.go", want: "file:///src/project/a.go"},
+		{name: "file without extension", path: "/src/project/Makefile", want: "file:///src/project/Makefile"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fileURI(tt.path, tt.dir)
+			if err != nil {
+				t.Fatalf("fileURI() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("fileURI() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package presenters
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"github.com/MikeMwita/go-strict/services/complexity"
+	"io"
+	"strings"
+)
+
+// Text renders the summary followed by every result and its complexity breakdown
+type Text struct{}
+
+// Format writes the report in a detailed, structured format
+func (t *Text) Format(w io.Writer, report *models.Report) error {
+	out := bufio.NewWriter(w)
+	WriteSummary(out, report.Summary())
+
+	for _, result := range report.Results {
+		column := result.Column
+		if column == 0 {
+			column = 1
+		}
+
+		if result.Function == "" {
+			fmt.Fprintf(out, "%s:%d:%d - %s: %s\n", result.File, result.Line, column, result.Severity, result.Message)
+		} else {
+			fmt.Fprintf(out, "%s:%d:%d - %s: %s\n", result.File, result.Line, column, result.Severity, result.Function)
+		}
+
+		// Print the details of the complexity
+		if result.Complexity == nil {
+			if result.Function != "" {
+				fmt.Fprintf(out, "  %s\n", result.Message)
+			}
+		} else {
+			for _, line := range strings.Split(strings.TrimSuffix(complexity.GetDetail(result), "\n"), "\n") {
+				fmt.Fprintf(out, "  %s\n", line)
+			}
+		}
+
+		fmt.Fprintln(out)
+	}
+	return out.Flush()
+}
+
+// WriteSummary writes the statistics over all analysed functions
+func WriteSummary(w io.Writer, summary *models.Summary) {
+	fmt.Fprintf(w, "Number of files: %d\n", summary.Files)
+	fmt.Fprintf(w, "Number of functions: %d\n", summary.Functions)
+	fmt.Fprintf(w, "Functions above the threshold: %d\n", summary.Findings)
+	if summary.Suppressed > 0 {
+		fmt.Fprintf(w, "Suppressed results: %d\n", summary.Suppressed)
+	}
+	fmt.Fprintf(w, "Highest complexity: %d\n", summary.HighestComplexity)
+	fmt.Fprintf(w, "Overall average complexity per function: %.2f\n", summary.Average)
+	fmt.Fprintf(w, "Median complexity: %.1f\n", summary.Median)
+	fmt.Fprintf(w, "90th percentile complexity: %d\n", summary.P90)
+	fmt.Fprintf(w, "99th percentile complexity: %d\n", summary.P99)
+	fmt.Fprintln(w, "Complexity histogram:")
+	for _, bucket := range summary.Histogram {
+		fmt.Fprintf(w, "  %-6s %d\n", bucket.Label(), bucket.Count)
+	}
+	fmt.Fprintln(w)
+}
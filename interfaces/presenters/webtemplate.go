@@ -1,33 +1,228 @@
 package presenters
 
 import (
+	"bytes"
+	_ "embed"
+	"fmt"
 	"github.com/MikeMwita/go-strict/models"
 	"html/template"
-	"net/http"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 )
 
-// WebTemplate  renders the linting results as HTML
+//go:embed templates/report.html
+var defaultTemplate string
 
+// WebTemplate renders a report as a single, self-contained HTML file
 type WebTemplate struct {
 	template *template.Template // the HTML template
+	// BaseDir is the directory that file names are relative to
+	BaseDir string
 }
 
-// Render renders the linting results as HTML to the given response writer
-func (wt *WebTemplate) Render(w http.ResponseWriter, results []*models.LintResult) error {
-	err := wt.template.Execute(w, results)
+// htmlReport is the data that is passed to the HTML template
+type htmlReport struct {
+	Title     string
+	Summary   *models.Summary
+	Histogram []htmlBucket
+	Packages  []*htmlPackage
+	// Functions are all analysed functions, the most complex first
+	Functions []*htmlFunction
+	// Findings are the functions above the threshold with their source
+	Findings []*htmlFunction
+	// Other are the results of all rules but complexity
+	Other []*models.LintResult
+}
+
+type htmlBucket struct {
+	models.HistogramBucket
+	Label   string
+	Percent int
+}
+
+type htmlPackage struct {
+	Name              string
+	Files             int
+	Functions         int
+	Findings          int
+	TotalComplexity   int
+	HighestComplexity int
+	Average           float64
+}
+
+type htmlFunction struct {
+	Anchor     string
+	Name       string
+	Package    string
+	File       string
+	Line       int
+	Complexity int
+	Severity   string
+	Message    string
+	Finding    *models.LintResult
+	Source     []htmlSourceLine
+}
+
+// htmlSourceLine is a line of a function, Increments lists what it adds to the complexity
+type htmlSourceLine struct {
+	Number     int
+	Text       string
+	Increments string
+}
+
+// Render renders the report as HTML to the given writer
+func (wt *WebTemplate) Render(w io.Writer, report *models.Report) error {
+	data, err := wt.data(report)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	// render into a buffer so a failing template does not leave half a page behind
+	var buf bytes.Buffer
+	if err := wt.template.Execute(&buf, data); err != nil {
+		return err
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// Format renders the report as HTML
+func (wt *WebTemplate) Format(w io.Writer, report *models.Report) error {
+	return wt.Render(w, report)
 }
 
+func (wt *WebTemplate) data(report *models.Report) (*htmlReport, error) {
+	summary := report.Summary()
+	data := &htmlReport{Title: "Cognitive complexity report", Summary: summary}
+
+	highest := 0
+	for _, bucket := range summary.Histogram {
+		if bucket.Count > highest {
+			highest = bucket.Count
+		}
+	}
+	for _, bucket := range summary.Histogram {
+		percent := 0
+		if highest > 0 {
+			percent = bucket.Count * 100 / highest
+		}
+		data.Histogram = append(data.Histogram, htmlBucket{HistogramBucket: bucket, Label: bucket.Label(), Percent: percent})
+	}
+
+	findings := make(map[functionKey]*models.LintResult)
+	for _, result := range report.Results {
+		if result.Complexity != nil {
+			findings[functionKey{result.File, result.Line, result.Function}] = result
+		} else {
+			other := *result
+			other.File = relativePath(wt.BaseDir, result.File)
+			data.Other = append(data.Other, &other)
+		}
+	}
+
+	packages := make(map[string]*htmlPackage)
+	sources := make(map[string][]string)
+	for _, file := range report.Files {
+		name := packageName(wt.BaseDir, file)
+		pkg, ok := packages[name]
+		if !ok {
+			pkg = &htmlPackage{Name: name}
+			packages[name] = pkg
+			data.Packages = append(data.Packages, pkg)
+		}
+		pkg.Files++
+
+		for _, function := range file.Functions {
+			fn := &htmlFunction{
+				Anchor:     fmt.Sprintf("f%d", len(data.Functions)+1),
+				Name:       functionName(function),
+				Package:    name,
+				File:       relativePath(wt.BaseDir, file.Path),
+				Line:       function.Line,
+				Complexity: function.Complexity,
+			}
+			data.Functions = append(data.Functions, fn)
+
+			pkg.Functions++
+			pkg.TotalComplexity += function.Complexity
+			if function.Complexity > pkg.HighestComplexity {
+				pkg.HighestComplexity = function.Complexity
+			}
+
+			result, ok := findings[functionKey{file.Path, function.Line, function.Name}]
+			if !ok {
+				continue
+			}
+			pkg.Findings++
+
+			lines, ok := sources[file.Path]
+			if !ok {
+				src, err := os.ReadFile(file.Path)
+				if err != nil {
+					return nil, fmt.Errorf("reading source of %s: %w", file.Path, err)
+				}
+				lines = strings.Split(string(src), "\n")
+				sources[file.Path] = lines
+			}
+
+			fn.Finding = result
+			fn.Severity = result.Severity
+			fn.Message = result.Message
+			fn.Source = sourceLines(lines, function.Line, function.EndLine, result.Complexity.Increments)
+			data.Findings = append(data.Findings, fn)
+		}
+	}
+
+	for _, pkg := range data.Packages {
+		if pkg.Functions > 0 {
+			pkg.Average = float64(pkg.TotalComplexity) / float64(pkg.Functions)
+		}
+	}
+	sort.SliceStable(data.Functions, func(i, j int) bool {
+		return data.Functions[i].Complexity > data.Functions[j].Complexity
+	})
+	sort.SliceStable(data.Findings, func(i, j int) bool {
+		return data.Findings[i].Complexity > data.Findings[j].Complexity
+	})
+	return data, nil
+}
+
+// sourceLines returns the lines from start to end, annotated with the increments found on them
+func sourceLines(lines []string, start, end int, increments []models.ComplexityIncrement) []htmlSourceLine {
+	annotations := make(map[int][]string)
+	for _, inc := range increments {
+		annotations[inc.Line] = append(annotations[inc.Line], fmt.Sprintf("+%d %s", inc.Increment, inc.Kind))
+	}
+
+	var source []htmlSourceLine
+	for number := start; number <= end && number <= len(lines); number++ {
+		source = append(source, htmlSourceLine{
+			Number:     number,
+			Text:       strings.TrimRight(lines[number-1], "\r"),
+			Increments: strings.Join(annotations[number], ", "),
+		})
+	}
+	return source
+}
+
+// NewWebTemplate parses the template at templatePath, or the embedded default
+// template if templatePath is empty
 func NewWebTemplate(templatePath string) (*WebTemplate, error) {
-	template, err := template.ParseFiles(templatePath)
+	var tmpl *template.Template
+	var err error
+	if templatePath == "" {
+		tmpl, err = template.New("report.html").Parse(defaultTemplate)
+	} else {
+		tmpl, err = template.New(filepath.Base(templatePath)).ParseFiles(templatePath)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	return &WebTemplate{
-		template: template,
+		template: tmpl,
 	}, nil
 }
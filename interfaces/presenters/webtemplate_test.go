@@ -0,0 +1,97 @@
+package presenters
+
+import (
+	"bytes"
+	"github.com/MikeMwita/go-strict/models"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWebTemplate_Render(t *testing.T) {
+	dir := t.TempDir()
+	source := "package server\n\nfunc Serve(ok bool) {\n\tif ok {\n\t\treturn\n\t}\n}\n"
+	path := filepath.Join(dir, "server", "server.go")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report := &models.Report{
+		Files: []*models.FileReport{{
+			Path:    path,
+			Package: "server",
+			Functions: []*models.FunctionReport{
+				{Name: "Serve", Line: 3, EndLine: 7, Complexity: 1},
+				{Name: "Stop", Receiver: "Server", Line: 9, EndLine: 9},
+			},
+		}},
+		Results: []*models.LintResult{
+			{
+				File:     path,
+				Line:     3,
+				Function: "Serve",
+				Severity: models.SeverityWarning,
+				Message:  "complexity <1>",
+				Complexity: &models.ComplexityReport{
+					Score:      1,
+					Increments: []models.ComplexityIncrement{{Kind: "if", Line: 4, Increment: 1, Total: 1}},
+				},
+			},
+			{File: path, Line: 5, Rule: "lll", Severity: models.SeverityInfo, Message: "too long"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+	}{
+		{
+			name: "embedded template",
+			want: []string{
+				"<td>server</td>",
+				"<td>Server.Stop</td>",
+				`<a href="#f1">Serve</a>`,
+				"server/server.go:3 &mdash; complexity &lt;1&gt;",
+				`<span class="hit"><span class="ln">4</span>	if ok {<span class="inc">&#43;1 if</span></span>`,
+				"<td>lll</td>",
+			},
+		},
+		{
+			name:     "custom template",
+			template: "{{range .Functions}}{{.Name}}={{.Complexity}};{{end}}",
+			want:     []string{"Serve=1;Server.Stop=0;"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templatePath := ""
+			if tt.template != "" {
+				templatePath = filepath.Join(dir, "custom.html")
+				if err := os.WriteFile(templatePath, []byte(tt.template), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			wt, err := NewWebTemplate(templatePath)
+			if err != nil {
+				t.Fatalf("NewWebTemplate() error = %v", err)
+			}
+			wt.BaseDir = dir
+
+			var buf bytes.Buffer
+			if err := wt.Render(&buf, report); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("Render() does not contain %q", want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// version is the format version of baseline files
+const version = 1
+
+// Entry is a grandfathered finding. Entries are keyed by package, receiver,
+// function and rule rather than by line, so they survive unrelated edits.
+type Entry struct {
+	// Package is the slash separated directory of the package relative to the base directory
+	Package  string `json:"package"`
+	Receiver string `json:"receiver,omitempty"`
+	Function string `json:"function,omitempty"`
+	Rule     string `json:"rule"`
+	// Complexity is the recorded score; a higher score is reported again
+	Complexity int `json:"complexity,omitempty"`
+	// Count is the number of findings of a rule without a function, e.g. long lines
+	Count int `json:"count,omitempty"`
+}
+
+func (e *Entry) key() string {
+	return strings.Join([]string{e.Package, e.Receiver, e.Function, e.Rule}, "\x00")
+}
+
+// String describes the entry, e.g. "internal/linter Server.Serve (complexity)"
+func (e *Entry) String() string {
+	name := e.Function
+	if e.Receiver != "" {
+		name = e.Receiver + "." + name
+	}
+	if name == "" {
+		return fmt.Sprintf("%s (%s)", e.Package, e.Rule)
+	}
+	return fmt.Sprintf("%s %s (%s)", e.Package, name, e.Rule)
+}
+
+// Baseline is a snapshot of the findings of a report
+type Baseline struct {
+	Version int      `json:"version"`
+	Entries []*Entry `json:"entries"`
+}
+
+// Outcome is the result of applying a baseline to a report
+type Outcome struct {
+	// Suppressed is the number of findings covered by the baseline
+	Suppressed int
+	// Grown are the baselined findings reported again because their complexity increased
+	Grown int
+	// Stale are the entries that no longer match a finding
+	Stale []*Entry
+}
+
+// New snapshots the findings of the report. Packages are relative to baseDir.
+func New(report *models.Report, baseDir string) *Baseline {
+	entries := make(map[string]*Entry)
+	for _, result := range report.Results {
+		entry := entryOf(report, result, baseDir)
+		if existing, ok := entries[entry.key()]; ok {
+			existing.Count++
+			if entry.Complexity > existing.Complexity {
+				existing.Complexity = entry.Complexity
+			}
+			continue
+		}
+		entries[entry.key()] = entry
+	}
+
+	b := &Baseline{Version: version, Entries: make([]*Entry, 0, len(entries))}
+	for _, entry := range entries {
+		if entry.Count == 1 {
+			entry.Count = 0
+		}
+		b.Entries = append(b.Entries, entry)
+	}
+	sort.Slice(b.Entries, func(i, j int) bool {
+		return b.Entries[i].key() < b.Entries[j].key()
+	})
+	return b
+}
+
+// Load reads a baseline file
+func Load(file string) (*Baseline, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", file, err)
+	}
+	if b.Version != version {
+		return nil, fmt.Errorf("unsupported baseline version %d in %s", b.Version, file)
+	}
+	return &b, nil
+}
+
+// Write saves the baseline as indented JSON
+func (b *Baseline) Write(file string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0o644)
+}
+
+// Apply removes the findings covered by the baseline from the report. Findings
+// whose complexity grew beyond the recorded score stay in the report.
+func (b *Baseline) Apply(report *models.Report, baseDir string) *Outcome {
+	remaining := make(map[string]int)
+	entries := make(map[string]*Entry)
+	for _, entry := range b.Entries {
+		entries[entry.key()] = entry
+		remaining[entry.key()] += max(entry.Count, 1)
+	}
+
+	outcome := &Outcome{}
+	var kept []*models.LintResult
+	for _, result := range report.Results {
+		key := entryOf(report, result, baseDir).key()
+		entry, ok := entries[key]
+		if !ok || remaining[key] == 0 {
+			kept = append(kept, result)
+			continue
+		}
+		remaining[key]--
+
+		if result.Complexity != nil && result.Complexity.Score > entry.Complexity {
+			result.Message += fmt.Sprintf(" (grew from %d in the baseline)", entry.Complexity)
+			outcome.Grown++
+			kept = append(kept, result)
+			continue
+		}
+		outcome.Suppressed++
+	}
+	report.Results = kept
+
+	for _, entry := range b.Entries {
+		if remaining[entry.key()] == max(entry.Count, 1) {
+			outcome.Stale = append(outcome.Stale, entry)
+		}
+	}
+	return outcome
+}
+
+// entryOf returns the baseline entry that matches a result
+func entryOf(report *models.Report, result *models.LintResult, baseDir string) *Entry {
+	entry := &Entry{
+		Package:  packageDir(baseDir, result.File),
+		Function: result.Function,
+		Rule:     result.Rule,
+		Count:    1,
+	}
+	if result.Complexity != nil {
+		entry.Complexity = result.Complexity.Score
+	}
+
+	if result.Function == "" {
+		return entry
+	}
+	for _, file := range report.Files {
+		if file.Path != result.File {
+			continue
+		}
+		for _, function := range file.Functions {
+			if function.Name == result.Function && function.Line <= result.Line && result.Line <= function.EndLine {
+				entry.Receiver = function.Receiver
+			}
+		}
+	}
+	return entry
+}
+
+// packageDir returns the slash separated directory of file relative to baseDir
+func packageDir(baseDir, file string) string {
+	dir := filepath.Dir(file)
+	if rel, err := filepath.Rel(baseDir, dir); err == nil && !strings.HasPrefix(rel, "..") {
+		dir = rel
+	}
+	return path.Clean(filepath.ToSlash(dir))
+}
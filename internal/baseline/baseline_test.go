@@ -0,0 +1,125 @@
+package baseline
+
+import (
+	"github.com/MikeMwita/go-strict/models"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testReport returns a report of /repo with a method above the threshold and two long lines
+func testReport(score int) *models.Report {
+	file := "/repo/internal/server/server.go"
+	return &models.Report{
+		Files: []*models.FileReport{{
+			Path:    file,
+			Package: "server",
+			Functions: []*models.FunctionReport{
+				{Name: "Serve", Receiver: "Server", Line: 10, EndLine: 40, Complexity: score},
+			},
+		}},
+		Results: []*models.LintResult{
+			{File: file, Line: 10, Function: "Serve", Rule: "complexity", Message: "too complex",
+				Complexity: &models.ComplexityReport{Score: score, Threshold: 10}},
+			{File: file, Line: 12, Rule: "lll"},
+			{File: file, Line: 13, Rule: "lll"},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	got := New(testReport(15), "/repo")
+	want := &Baseline{Version: version, Entries: []*Entry{
+		{Package: "internal/server", Rule: "lll", Count: 2},
+		{Package: "internal/server", Receiver: "Server", Function: "Serve", Rule: "complexity", Complexity: 15},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("New() = %+v, want %+v", got.Entries, want.Entries)
+	}
+}
+
+func TestBaseline_WriteLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	b := New(testReport(15), "/repo")
+	if err := b.Write(path); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, b) {
+		t.Errorf("Load() = %+v, want %+v", got, b)
+	}
+}
+
+func TestBaseline_Apply(t *testing.T) {
+	b := New(testReport(15), "/repo")
+
+	tests := []struct {
+		name        string
+		report      *models.Report
+		wantResults int
+		want        *Outcome
+	}{
+		{
+			name:   "unchanged",
+			report: testReport(15),
+			want:   &Outcome{Suppressed: 3},
+		},
+		{
+			name:   "less complex",
+			report: testReport(12),
+			want:   &Outcome{Suppressed: 3},
+		},
+		{
+			name:        "grown",
+			report:      testReport(18),
+			wantResults: 1,
+			want:        &Outcome{Suppressed: 2, Grown: 1},
+		},
+		{
+			name: "fixed",
+			report: func() *models.Report {
+				r := testReport(15)
+				r.Results = r.Results[1:2]
+				return r
+			}(),
+			want: &Outcome{Suppressed: 1, Stale: []*Entry{b.Entries[1]}},
+		},
+		{
+			name: "new long line",
+			report: func() *models.Report {
+				r := testReport(15)
+				r.Results = append(r.Results, &models.LintResult{File: "/repo/internal/server/server.go", Line: 50, Rule: "lll"})
+				return r
+			}(),
+			wantResults: 1,
+			want:        &Outcome{Suppressed: 3},
+		},
+		{
+			name: "moved package",
+			report: func() *models.Report {
+				r := testReport(15)
+				r.Files[0].Path = "/repo/server/server.go"
+				for _, result := range r.Results {
+					result.File = r.Files[0].Path
+				}
+				return r
+			}(),
+			wantResults: 3,
+			want:        &Outcome{Stale: b.Entries},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := b.Apply(tt.report, "/repo")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", got, tt.want)
+			}
+			if len(tt.report.Results) != tt.wantResults {
+				t.Errorf("Apply() left %d results, want %d", len(tt.report.Results), tt.wantResults)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// Entry is the cached analysis of a single file. Paths are not stored, so an
+// entry is valid for any file with the same content.
+type Entry struct {
+	File    *models.FileReport   `json:"file"`
+	Results []*models.LintResult `json:"results"`
+}
+
+// format is the version of the layout of the entries on disk. Changes to the
+// analysis itself are covered by the build version in every key.
+const format = 2
+
+// entriesPrefix starts the name of the directories below the cache dir that hold
+// the entries. Only these directories are removed by Clear, so a cache dir that
+// is shared with other files is safe to clear.
+const entriesPrefix = "go-strict-cache-v"
+
+// Cache stores analyses on disk, keyed by the content of a file, the effective
+// config and the build version. It is safe for concurrent use.
+type Cache struct {
+	dir     string
+	version string
+	hits    atomic.Int64
+	misses  atomic.Int64
+}
+
+// DefaultDir returns the cache directory below the user cache dir, e.g. ~/.cache/go-strict
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-strict"), nil
+}
+
+// BuildVersion identifies the build of the running binary, so entries written by
+// any other build, e.g. one that scores functions differently, are never used. It
+// is the module version and checksum of an installed release, the VCS revision of
+// a clean checkout, or else a hash of the executable.
+func BuildVersion() (string, error) {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Sum != "" {
+			return info.Main.Version + " " + info.Main.Sum, nil
+		}
+		settings := make(map[string]string)
+		for _, setting := range info.Settings {
+			settings[setting.Key] = setting.Value
+		}
+		if revision := settings["vcs.revision"]; revision != "" && settings["vcs.modified"] == "false" {
+			return "vcs " + revision, nil
+		}
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("identifying the build: %w", err)
+	}
+	f, err := os.Open(exe)
+	if err != nil {
+		return "", fmt.Errorf("identifying the build: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("identifying the build: %w", err)
+	}
+	return "exe " + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// New returns a cache in dir for the given build version, see BuildVersion
+func New(dir, version string) *Cache {
+	return &Cache{dir: dir, version: version}
+}
+
+// Dir returns the directory of the cache
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Key derives the key of a file from its content and a hash of the config
+func (c *Cache) Key(content []byte, configHash string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", c.version, configHash)
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// entries returns the directory of the entries of the current format
+func (c *Cache) entries() string {
+	return filepath.Join(c.dir, fmt.Sprintf("%s%d", entriesPrefix, format))
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.entries(), key[:2], key+".json")
+}
+
+// Get returns the entry of key. Missing and unreadable entries are misses.
+func (c *Cache) Get(key string) (*Entry, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.File == nil {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	return &entry, true
+}
+
+// Put stores the entry under key. The entry is written to a temporary file
+// first, so concurrent readers never see a partial entry.
+func (c *Cache) Put(key string, entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Clear removes every entry of the cache, including those of older formats.
+// Other files in the cache dir are left alone.
+func (c *Cache) Clear() error {
+	dirs, err := filepath.Glob(filepath.Join(c.dir, entriesPrefix+"*"))
+	if err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		if err := os.RemoveAll(dir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stats returns the number of cache hits and misses so far
+func (c *Cache) Stats() (hits, misses int64) {
+	return c.hits.Load(), c.misses.Load()
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"github.com/MikeMwita/go-strict/models"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "cache"), "1.0.0")
+	key := c.Key([]byte("package main\n"), "config")
+
+	if _, ok := c.Get(key); ok {
+		t.Fatalf("Get() on an empty cache is a hit")
+	}
+
+	entry := &Entry{
+		File:    &models.FileReport{Package: "main", Functions: []*models.FunctionReport{{Name: "main", Line: 3, EndLine: 5}}},
+		Results: []*models.LintResult{{Line: 3, Function: "main", Rule: "complexity"}},
+	}
+	if err := c.Put(key, entry); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	got, ok := c.Get(key)
+	if !ok || !reflect.DeepEqual(got, entry) {
+		t.Errorf("Get() = %v, %v, want %v", got, ok, entry)
+	}
+	if hits, misses := c.Stats(); hits != 1 || misses != 1 {
+		t.Errorf("Stats() = %d, %d, want 1, 1", hits, misses)
+	}
+
+	if err := os.WriteFile(c.path(key), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get(key); ok {
+		t.Errorf("Get() of a corrupt entry is a hit")
+	}
+
+	notes := filepath.Join(c.Dir(), "notes.txt")
+	if err := os.WriteFile(notes, []byte("not ours"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if _, err := os.Stat(c.entries()); !os.IsNotExist(err) {
+		t.Errorf("Clear() left %s behind", c.entries())
+	}
+	if _, err := os.Stat(notes); err != nil {
+		t.Errorf("Clear() removed a file it did not write: %v", err)
+	}
+}
+
+func TestCache_Key(t *testing.T) {
+	content := []byte("package main\n")
+	key := New("", "1.0.0").Key(content, "config")
+
+	tests := []struct {
+		name    string
+		version string
+		content string
+		config  string
+		same    bool
+	}{
+		{name: "same input", version: "1.0.0", content: "package main\n", config: "config", same: true},
+		{name: "other content", version: "1.0.0", content: "package other\n", config: "config"},
+		{name: "other config", version: "1.0.0", content: "package main\n", config: "threshold=1"},
+		{name: "other version", version: "1.1.0", content: "package main\n", config: "config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New("", tt.version).Key([]byte(tt.content), tt.config)
+			if (got == key) != tt.same {
+				t.Errorf("Key() = %s, same as the original key: %v, want %v", got, got == key, tt.same)
+			}
+		})
+	}
+}
+
+func TestBuildVersion(t *testing.T) {
+	first, err := BuildVersion()
+	if err != nil {
+		t.Fatalf("BuildVersion() error = %v", err)
+	}
+	if first == "" {
+		t.Fatalf("BuildVersion() is empty")
+	}
+	if second, _ := BuildVersion(); second != first {
+		t.Errorf("BuildVersion() = %q, then %q, want a stable version", first, second)
+	}
+}
@@ -0,0 +1,272 @@
+package diff
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"io"
+	"math"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LineRange is an inclusive range of lines of the new version of a file
+type LineRange struct {
+	Start, End int
+}
+
+// FileDiff lists the changed lines of a single file
+type FileDiff struct {
+	// OldPath is empty for added files
+	OldPath string
+	NewPath string
+	Ranges  []LineRange
+}
+
+// Diff is a parsed unified diff, keyed by the slash separated new path of each file
+type Diff struct {
+	Files map[string]*FileDiff
+}
+
+// Parse reads a unified diff as written by git diff or diff -u. Added lines are
+// changes; removed lines mark the line that follows them in the new file.
+// Deleted files are skipped.
+func Parse(r io.Reader) (*Diff, error) {
+	d := &Diff{Files: make(map[string]*FileDiff)}
+	var file *FileDiff
+	var oldPath string
+	// the lines of the current hunk that are still to come
+	var h hunk
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNo++
+
+		if h.oldLines > 0 || h.newLines > 0 {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				file.add(h.newStart)
+				h.newStart++
+				h.newLines--
+			case strings.HasPrefix(line, "-"):
+				file.add(h.newStart)
+				h.oldLines--
+			case strings.HasPrefix(line, "\\"):
+				// "\ No newline at end of file"
+			default:
+				h.newStart++
+				h.oldLines--
+				h.newLines--
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff "):
+			file, oldPath = nil, ""
+		case strings.HasPrefix(line, "--- "):
+			oldPath = diffPath(line[4:])
+		case strings.HasPrefix(line, "+++ "):
+			newPath := diffPath(line[4:])
+			file = nil
+			if newPath == "" {
+				continue
+			}
+			file = d.Files[newPath]
+			if file == nil {
+				file = &FileDiff{OldPath: oldPath, NewPath: newPath}
+				d.Files[newPath] = file
+			}
+		case strings.HasPrefix(line, "@@"):
+			var err error
+			if h, err = parseHunk(line); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			if file == nil {
+				// the hunk of a deleted file
+				file = &FileDiff{}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// hunk counts the lines of a hunk that have not been read yet
+type hunk struct {
+	oldLines int
+	newStart int
+	newLines int
+}
+
+// ParseBytes parses a unified diff held in memory
+func ParseBytes(patch []byte) (*Diff, error) {
+	return Parse(bytes.NewReader(patch))
+}
+
+// add marks a line as changed, extending the last range where possible
+func (f *FileDiff) add(line int) {
+	if n := len(f.Ranges); n > 0 && line >= f.Ranges[n-1].Start && line <= f.Ranges[n-1].End+1 {
+		if line > f.Ranges[n-1].End {
+			f.Ranges[n-1].End = line
+		}
+		return
+	}
+	f.Ranges = append(f.Ranges, LineRange{Start: line, End: line})
+}
+
+// Touches reports whether any line from start to end of the file was changed
+func (d *Diff) Touches(file string, start, end int) bool {
+	f, ok := d.Files[path.Clean(file)]
+	if !ok {
+		return false
+	}
+	if end < start {
+		end = start
+	}
+	for _, r := range f.Ranges {
+		if r.Start <= end && r.End >= start {
+			return true
+		}
+	}
+	return false
+}
+
+// diffPath returns the path of a ---/+++ header without the a/ or b/ prefix
+// and trailing timestamp, or "" for /dev/null
+func diffPath(header string) string {
+	if i := strings.IndexByte(header, '\t'); i >= 0 {
+		header = header[:i]
+	}
+	header = strings.TrimSpace(header)
+	if unquoted, err := strconv.Unquote(header); err == nil {
+		header = unquoted
+	}
+	if header == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(header, "a/") || strings.HasPrefix(header, "b/") {
+		header = header[2:]
+	}
+	return path.Clean(header)
+}
+
+// parseHunk parses a "@@ -l,s +l,s @@" header; a missing size means one line
+func parseHunk(header string) (hunk, error) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return hunk{}, fmt.Errorf("malformed hunk header %q", header)
+	}
+	_, oldLines, err := hunkRange(fields[1][1:])
+	if err != nil {
+		return hunk{}, fmt.Errorf("malformed hunk header %q", header)
+	}
+	newStart, newLines, err := hunkRange(fields[2][1:])
+	if err != nil {
+		return hunk{}, fmt.Errorf("malformed hunk header %q", header)
+	}
+	if newLines == 0 {
+		// lines were only removed, after line newStart
+		newStart++
+	}
+	return hunk{oldLines: oldLines, newStart: newStart, newLines: newLines}, nil
+}
+
+func hunkRange(r string) (start, lines int, err error) {
+	startText, linesText, found := strings.Cut(r, ",")
+	if start, err = strconv.Atoi(startText); err != nil {
+		return 0, 0, err
+	}
+	if !found {
+		return start, 1, nil
+	}
+	lines, err = strconv.Atoi(linesText)
+	return start, lines, err
+}
+
+// Git returns the changes of the working tree in dir since rev
+func Git(dir, rev string) ([]byte, error) {
+	return git(dir, "diff", "--no-color", "--no-ext-diff", "--unified=0", rev, "--")
+}
+
+// Untracked returns the slash separated paths, relative to the repository root
+// dir, of the files that git does not track and does not ignore. They are not
+// part of the output of Git, although they are new since any revision.
+func Untracked(dir string) ([]string, error) {
+	out, err := git(dir, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, file := range strings.Split(string(out), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// AddFile marks every line of the file at the slash separated path as added
+func (d *Diff) AddFile(file string) {
+	if d.Files == nil {
+		d.Files = make(map[string]*FileDiff)
+	}
+	file = path.Clean(file)
+	d.Files[file] = &FileDiff{NewPath: file, Ranges: []LineRange{{Start: 1, End: math.MaxInt}}}
+}
+
+// Show returns the content of the file at the slash separated path, relative
+// to the repository root, in rev
+func Show(dir, rev, file string) ([]byte, error) {
+	return git(dir, "show", rev+":"+file)
+}
+
+// Root returns the top level directory of the git repository containing dir
+func Root(dir string) (string, error) {
+	out, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// Filter returns the results whose lines were changed. Paths of results are
+// made relative to root, the directory the paths of the diff are relative to.
+func (d *Diff) Filter(results []*models.LintResult, root string) []*models.LintResult {
+	var filtered []*models.LintResult
+	for _, result := range results {
+		if d.Touches(RelativePath(root, result.File), result.Line, result.EndLine) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// RelativePath returns the slash separated path of file relative to root
+func RelativePath(root, file string) string {
+	if rel, err := filepath.Rel(root, file); err == nil && filepath.IsAbs(file) == filepath.IsAbs(root) {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(file)
+}
@@ -0,0 +1,95 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const patch = `diff --git a/pkg/a.go b/pkg/a.go
+index 1111111..2222222 100644
+--- a/pkg/a.go
++++ b/pkg/a.go
+@@ -3,7 +3,7 @@ import "fmt"
+ func A() {
+-	fmt.Println("a")
++	fmt.Println("A")
++	fmt.Println("b")
+ }
+ 
+ func B() {
+--- a comment that looks like a header
+ }
+@@ -20,2 +21,0 @@ func C() {
+-	x++
+-	y++
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package old
+-
+diff --git a/new.go b/new.go
+new file mode 100644
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1,2 @@
++package pkg
++
+\ No newline at end of file
+`
+
+func TestParse(t *testing.T) {
+	got, err := Parse(strings.NewReader(patch))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := map[string]*FileDiff{
+		"pkg/a.go": {OldPath: "pkg/a.go", NewPath: "pkg/a.go", Ranges: []LineRange{{4, 5}, {9, 9}, {22, 22}}},
+		"new.go":   {NewPath: "new.go", Ranges: []LineRange{{1, 2}}},
+	}
+	if !reflect.DeepEqual(got.Files, want) {
+		for path, file := range got.Files {
+			t.Logf("%s: %+v", path, file)
+		}
+		t.Errorf("Parse() files differ from %v", want)
+	}
+}
+
+func TestDiff_Touches(t *testing.T) {
+	d, err := Parse(strings.NewReader(patch))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		file       string
+		start, end int
+		want       bool
+	}{
+		{name: "changed function", file: "pkg/a.go", start: 3, end: 5, want: true},
+		{name: "removed line", file: "pkg/a.go", start: 8, end: 10, want: true},
+		{name: "untouched function", file: "pkg/a.go", start: 11, end: 20, want: false},
+		{name: "removed lines at the end", file: "pkg/a.go", start: 21, end: 23, want: true},
+		{name: "uncleaned path", file: "./pkg/a.go", start: 4, end: 4, want: true},
+		{name: "file not in the diff", file: "pkg/b.go", start: 1, end: 100, want: false},
+		{name: "deleted file", file: "old.go", start: 1, end: 2, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.Touches(tt.file, tt.start, tt.end); got != tt.want {
+				t.Errorf("Touches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_malformed(t *testing.T) {
+	_, err := Parse(strings.NewReader("--- a/x.go\n+++ b/x.go\n@@ -1 +x @@\n"))
+	if err == nil {
+		t.Errorf("Parse() error = nil, want an error for the malformed hunk header")
+	}
+}
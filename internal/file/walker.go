@@ -1,57 +1,187 @@
 package file
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
 	"io/fs"
 	"io/ioutil"
-	"math/rand"
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
-	"testing"
 )
 
-// Walker gets all files in the filterDir and directories below.
-// You can:
-//   - filter files like "*.go"
-//   - give a max. directory depth
-//     -1 = unrestricted deep
-//     0 = only filterDir
-//     1 = filterDir and 1 deeper
-//     etc.
-func Walker(filterDir, locFilter string, locDepth int) ([]string, error) {
-	var err error
-	fileList := []string{}
-	filterDir = strings.Replace(filterDir, "\\", "/", -1) // for windows
-	if !DirExists(filterDir) {
-		return fileList, err
+// Options filter the files returned by Walk
+type Options struct {
+	// Pattern matches the name of a file, e.g. "*.go"; empty matches every file
+	Pattern string
+	// Include are globs of the files to return; empty includes every file
+	Include []string
+	// Exclude are globs of the files and directories to skip
+	Exclude []string
+	// SkipTests skips _test.go files
+	SkipTests bool
+	// SkipVendor skips vendor directories
+	SkipVendor bool
+	// SkipGenerated skips files with a "// Code generated ... DO NOT EDIT." header
+	SkipGenerated bool
+	// MaxDepth is the number of directory levels that are walked; 1 means only
+	// the root directory and 0 is unrestricted
+	MaxDepth int
+}
+
+// Walk returns the files in root and the directories below it that pass the
+// options, in lexical order. Globs are matched against the slash separated path
+// relative to root; "**" matches any number of directories. A root that is a
+// file is returned as is.
+func Walk(root string, opts *Options) ([]string, error) {
+	for _, pattern := range append(append([]string{opts.Pattern}, opts.Include...), opts.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
 	}
-	fsys := os.DirFS(filterDir)
-	fs.WalkDir(fsys, ".", func(filePath string, d fs.DirEntry, err error) error {
+	if !info.IsDir() {
+		return []string{root}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, filePath)
 		if err != nil {
 			return err
 		}
+		rel = filepath.ToSlash(rel)
+
 		if d.IsDir() {
+			if rel == "." {
+				return nil
+			}
+			if (opts.SkipVendor && d.Name() == "vendor") || matchAny(opts.Exclude, rel) ||
+				(opts.MaxDepth > 0 && strings.Count(rel, "/")+1 >= opts.MaxDepth) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
-		_, fileName := path.Split(filePath)
-		matched, err := filepath.Match(locFilter, fileName)
-		if err != nil || !matched {
+
+		if opts.Pattern != "" {
+			if matched, _ := path.Match(opts.Pattern, d.Name()); !matched {
+				return nil
+			}
+		}
+		if opts.SkipTests && strings.HasSuffix(d.Name(), "_test.go") {
 			return nil
 		}
-		filePath = strings.ReplaceAll(filePath, "\\", "/") // for windows
-		if strings.Contains(filePath, "/vendor/") {
+		if (len(opts.Include) > 0 && !matchAny(opts.Include, rel)) || matchAny(opts.Exclude, rel) {
 			return nil
 		}
-		if locDepth >= 0 {
-			slashes := strings.Count(filePath, "/")
-			if slashes > locDepth {
+		if opts.SkipGenerated {
+			generated, err := IsGenerated(filePath)
+			if err != nil {
+				return err
+			}
+			if generated {
 				return nil
 			}
 		}
-		fileList = append(fileList, filterDir+"/"+filePath)
+
+		files = append(files, filePath)
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Match reports whether the slash separated name matches the glob. Besides the
+// syntax of path.Match, a "**" element matches zero or more path elements.
+func Match(pattern, name string) bool {
+	return matchElements(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchElements(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchElements(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], name[0]); !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if Match(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// generatedHeader is the comment that marks generated Go files, see https://go.dev/s/generatedcode
+var generatedHeader = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// IsGenerated reports whether the Go file has a generated code comment before its package clause
+func IsGenerated(filePath string) (bool, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := bytes.TrimRight(scanner.Bytes(), "\r")
+		if generatedHeader.Match(line) {
+			return true, nil
+		}
+		if bytes.HasPrefix(line, []byte("package ")) {
+			return false, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// Walker gets all files in the filterDir and directories below.
+// You can:
+//   - filter files like "*.go"
+//   - give a max. directory depth
+//     -1 = unrestricted deep
+//     0 = only filterDir
+//     1 = filterDir and 1 deeper
+//     etc.
+func Walker(filterDir, locFilter string, locDepth int) ([]string, error) {
+	fileList := []string{}
+	filterDir = strings.Replace(filterDir, "\\", "/", -1) // for windows
+	if !DirExists(filterDir) {
+		return fileList, nil
+	}
+
+	files, err := Walk(filterDir, &Options{Pattern: locFilter, SkipVendor: true, MaxDepth: locDepth + 1})
+	for _, file := range files {
+		fileList = append(fileList, filepath.ToSlash(file))
+	}
 	return fileList, err
 }
 
@@ -81,46 +211,3 @@ func DirExists(dirPath string) bool {
 	}
 	return true
 }
-
-// Benchmark function
-
-func BenchmarkWalker(b *testing.B) {
-	rootDir, _ := RootDir()
-	// create some files first
-	testDir := rootDir + "/file/temp"
-
-	defer os.RemoveAll(testDir)
-
-	for i := 0; i < 10; i++ {
-		_ = createTestFile(testDir)
-	}
-
-	b.ResetTimer()
-
-	for i := 0; i < b.N; i++ { // use b.N for looping
-		_, _ = Walker(testDir, "*.txt", 0)
-	}
-}
-
-// Helper function
-func createTestFile(testDir string) error {
-	rsuffix := randomString(5)
-	testFile := testDir + "/testfile_" + rsuffix + ".txt"
-	err := TextWriter("Marcello Holland", testFile)
-	return err
-}
-
-// Other helper functions
-func randomString(length int) string {
-	bytes := make([]byte, length)
-
-	for i := 0; i < length; i++ {
-		bytes[i] = byte(randomInt(65, 90))
-	}
-
-	return string(bytes)
-}
-
-func randomInt(min, max int) int {
-	return min + rand.Intn(max-min)
-}
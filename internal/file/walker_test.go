@@ -1,193 +1,160 @@
-package file_test
+package file
 
-/* ------------------------------------ Imports -------------------------------- */
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
 
-/* ------------------------------------ Methods/Functions -------------------------------- */
+// writeTree creates the files below dir and returns dir
+func writeTree(tb testing.TB, files map[string]string) string {
+	tb.Helper()
+	dir := tb.TempDir()
+	for name, content := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			tb.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return dir
+}
 
-//func Test_Walker_Wrong_Directory(t *testing.T) {
-//	rootDir := libFile.RootDir()
-//
-//	// create some files first
-//	testDir := rootDir + "/file/temp"
-//
-//	files, err := libFile.Walker(testDir, "*.txt", 0)
-//	assert.NoError(t, err)
-//	assert.Len(t, files, 0)
-//}
-//
-//func Test_Walker_Single_Directory_Wrong_Filter(t *testing.T) {
-//	rootDir := libFile.RootDir()
-//
-//	// create some files first
-//	testDir := rootDir + "/file/temp"
-//	defer os.RemoveAll(testDir)
-//
-//	for i := 0; i < 10; i++ {
-//		err := createTestFile(testDir)
-//		assert.NoError(t, err)
-//	}
-//
-//	files, err := libFile.Walker(testDir, "*.wrong", 0)
-//	assert.NoError(t, err)
-//	assert.Len(t, files, 0)
-//}
-//
-//func Test_Walker_Single_Directory(t *testing.T) {
-//	rootDir := libFile.RootDir()
-//
-//	// create some files first
-//	testDir := rootDir + "/file/temp"
-//	defer os.RemoveAll(testDir)
-//
-//	for i := 0; i < 10; i++ {
-//		err := createTestFile(testDir)
-//		assert.NoError(t, err)
-//	}
-//
-//	// create some files in a subdirectory
-//	testSubDir := rootDir + "/file/temp/subdir"
-//	for i := 0; i < 5; i++ {
-//		err := createTestFile(testSubDir)
-//		assert.NoError(t, err)
-//	}
-//
-//	files, err := libFile.Walker(testDir, "*.txt", 0)
-//	assert.NoError(t, err)
-//	assert.Len(t, files, 10)
-//}
-//
-//func Test_Walker_SubDirectory(t *testing.T) {
-//	rootDir := libFile.RootDir()
-//
-//	// create some files first
-//	testDir := rootDir + "/file/temp"
-//	defer os.RemoveAll(testDir)
-//
-//	for i := 0; i < 10; i++ {
-//		err := createTestFile(testDir)
-//		assert.NoError(t, err)
-//	}
-//
-//	// create some files in a subdirectory
-//	testSubDir := rootDir + "/file/temp/subdir"
-//	for i := 0; i < 5; i++ {
-//		err := createTestFile(testSubDir)
-//		assert.NoError(t, err)
-//	}
-//
-//	// create some files in a subdirectory
-//	testDeeperSubDir := rootDir + "/file/temp/subdir/deeper/"
-//	for i := 0; i < 2; i++ {
-//		err := createTestFile(testDeeperSubDir)
-//		assert.NoError(t, err)
-//	}
-//
-//	files, err := libFile.Walker(testDir, "*.txt", 1)
-//	assert.NoError(t, err)
-//	assert.Len(t, files, 15)
-//}
-//
-//func Test_Walker_AllDirectories(t *testing.T) {
-//	rootDir := libFile.RootDir()
-//
-//	// create some files first
-//	testDir := rootDir + "/file/temp"
-//	defer os.RemoveAll(testDir)
-//
-//	for i := 0; i < 10; i++ {
-//		err := createTestFile(testDir)
-//		assert.NoError(t, err)
-//	}
-//
-//	// create some files in a subdirectory
-//	testSubDir := rootDir + "/file/temp/subdir"
-//	for i := 0; i < 5; i++ {
-//		err := createTestFile(testSubDir)
-//		assert.NoError(t, err)
-//	}
-//
-//	// create some files in a subdirectory
-//	testDeeperSubDir := rootDir + "/file/temp/subdir/deeper/"
-//	for i := 0; i < 2; i++ {
-//		err := createTestFile(testDeeperSubDir)
-//		assert.NoError(t, err)
-//	}
-//
-//	files, err := libFile.Walker(testDir, "*.txt", -1)
-//	assert.NoError(t, err)
-//	assert.Len(t, files, 17)
-//}
-//
-//// createTestFile creates a random test file.
-//func createTestFile(testDir string) error {
-//	rsuffix := randomString(5)
-//	testFile := testDir + "/testfile_" + rsuffix + ".txt"
-//	err := libFile.TextWriter("Marcello Holland", testFile)
-//
-//	return err
-//}
-//
-//// Returns an int >= min, < max.
-//func randomInt(min, max int) int {
-//	return min + rand.Intn(max-min)
-//}
-//
-//// Generate a random string of A-Z chars with len = l.
-//func randomString(length int) string {
-//	bytes := make([]byte, length)
-//
-//	for i := 0; i < length; i++ {
-//		bytes[i] = byte(randomInt(65, 90))
-//	}
-//
-//	return string(bytes)
-//}
-//
-//
-//func BenchmarkWalker(b *testing.B) {
-//	rootDir, _ := RootDir()
-//	// create some files first
-//	testDir := rootDir + "/file/temp"
-//
-//	defer os.RemoveAll(testDir)
-//
-//	for i := 0; i < 10; i++ {
-//		_ = createTestFile(testDir)
-//	}
-//
-//	b.ResetTimer()
-//
-//	for i := 0; i < b.N; i++ { // use b.N for looping
-//		_, _ = Walker(testDir, "*.txt", 0)
-//	}
-//}
-//
-//// Helper functions
-//func createTestFile(testDir string) error {
-//	rsuffix := randomString(5)
-//	testFile := testDir + "/testfile_" + rsuffix + ".txt"
-//	err := TextWriter("MikeMwita", testFile)
-//	return err
-//}
-//
-//func randomString(length int) string {
-//	bytes := make([]byte, length)
-//
-//	for i := 0; i < length; i++ {
-//		bytes[i] = byte(randomInt(65, 90))
-//	}
-//
-//	return string(bytes)
-//}
-//
-//func randomInt(min, max int) int {
-//	return min + rand.Intn(max-min)
-//}
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "main.go", name: "main.go", want: true},
+		{pattern: "*.go", name: "cmd/main.go", want: false},
+		{pattern: "**/*.go", name: "main.go", want: true},
+		{pattern: "**/*.go", name: "cmd/code/main.go", want: true},
+		{pattern: "**/testdata/**", name: "internal/linter/testdata/invalid.go", want: true},
+		{pattern: "**/testdata/**", name: "testdata", want: true},
+		{pattern: "internal/**/*_gen.go", name: "internal/a/b/c_gen.go", want: true},
+		{pattern: "internal/**/*_gen.go", name: "cmd/c_gen.go", want: false},
+		{pattern: "cmd/*", name: "cmd/code/cmd.go", want: false},
+		{pattern: "**", name: "a/b", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			if got := Match(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
 
-/*
-Benchmark_Walker-8   	  187741	     21920 ns/op	    5760 B/op	      97 allocs/op
-Benchmark_Walker-8   	   57690	     20103 ns/op	    5760 B/op	      97 allocs/op
+func TestWalk(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"main.go":                     "package main\n",
+		"main_test.go":                "package main\n",
+		"notes.txt":                   "notes\n",
+		"gen.go":                      "// Code generated by stringer; DO NOT EDIT.\n\npackage main\n",
+		"doc.go":                      "package main\n\n// Code generated by stringer; DO NOT EDIT.\n",
+		"vendor/dep/dep.go":           "package dep\n",
+		"internal/a/a.go":             "package a\n",
+		"internal/a/testdata/x.go":    "package x\n",
+		"internal/a/deeper/deeper.go": "package deeper\n",
+	})
 
-Benchmark_Walker-8   	   59488	     20287 ns/op	    5760 B/op	      97 allocs/op
+	tests := []struct {
+		name    string
+		opts    Options
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Test all Go files",
+			opts: Options{Pattern: "*.go"},
+			want: []string{"doc.go", "gen.go", "internal/a/a.go", "internal/a/deeper/deeper.go", "internal/a/testdata/x.go", "main.go", "main_test.go", "vendor/dep/dep.go"},
+		},
+		{
+			name: "Test skips",
+			opts: Options{Pattern: "*.go", SkipTests: true, SkipVendor: true, SkipGenerated: true},
+			want: []string{"doc.go", "internal/a/a.go", "internal/a/deeper/deeper.go", "internal/a/testdata/x.go", "main.go"},
+		},
+		{
+			name: "Test include and exclude",
+			opts: Options{Pattern: "*.go", Include: []string{"internal/**"}, Exclude: []string{"**/testdata/**", "**/deeper"}},
+			want: []string{"internal/a/a.go"},
+		},
+		{
+			name: "Test max depth",
+			opts: Options{Pattern: "*.go", MaxDepth: 3},
+			want: []string{"doc.go", "gen.go", "internal/a/a.go", "main.go", "main_test.go", "vendor/dep/dep.go"},
+		},
+		{
+			name:    "Test invalid glob",
+			opts:    Options{Exclude: []string{"[a"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := Walk(dir, &tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Walk() error = %v, wantErr %v", err, tt.wantErr)
+			}
 
-*/
+			var got []string
+			for _, file := range files {
+				rel, _ := filepath.Rel(dir, file)
+				got = append(got, filepath.ToSlash(rel))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Walk() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalker(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.txt":              "a",
+		"b.go":               "b",
+		"sub/c.txt":          "c",
+		"sub/deeper/d.txt":   "d",
+		"sub/vendor/e/e.txt": "e",
+	})
+
+	tests := []struct {
+		name  string
+		dir   string
+		depth int
+		want  int
+	}{
+		{name: "Test wrong directory", dir: filepath.Join(dir, "missing-dir"), depth: 0, want: 0},
+		{name: "Test single directory", dir: dir, depth: 0, want: 1},
+		{name: "Test subdirectory", dir: dir, depth: 1, want: 2},
+		{name: "Test all directories", dir: dir, depth: -1, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := Walker(tt.dir, "*.txt", tt.depth)
+			if err != nil {
+				t.Fatalf("Walker() error = %v", err)
+			}
+			if len(files) != tt.want {
+				t.Errorf("Walker() = %v, want %d files", files, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkWalker(b *testing.B) {
+	files := make(map[string]string)
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		files["testfile_"+name+".txt"] = "MikeMwita"
+	}
+	testDir := writeTree(b, files)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Walker(testDir, "*.txt", 0)
+	}
+}
@@ -0,0 +1,141 @@
+package linter
+
+import (
+	"fmt"
+	"github.com/MikeMwita/go-strict/internal/cache"
+	"github.com/MikeMwita/go-strict/models"
+	"github.com/MikeMwita/go-strict/services/complexity"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writePackages generates packages of files with functions of increasing complexity
+func writePackages(tb testing.TB, packages, files int) string {
+	tb.Helper()
+	dir := tb.TempDir()
+	for p := 0; p < packages; p++ {
+		pkgDir := filepath.Join(dir, fmt.Sprintf("pkg%03d", p))
+		if err := os.Mkdir(pkgDir, 0o755); err != nil {
+			tb.Fatal(err)
+		}
+		for f := 0; f < files; f++ {
+			var src strings.Builder
+			fmt.Fprintf(&src, "package pkg%03d\n", p)
+			for fn := 0; fn < 10; fn++ {
+				fmt.Fprintf(&src, "\nfunc F%d_%d(xs []int, ok bool) int {\n\tn := 0\n", f, fn)
+				for depth := 0; depth < fn%5; depth++ {
+					src.WriteString("\tfor _, x := range xs {\n\t\tif x > 0 && ok || x < -1 {\n\t\t\tn++\n\t\t}\n\t}\n")
+				}
+				src.WriteString("\treturn n\n}\n")
+			}
+			if err := os.WriteFile(filepath.Join(pkgDir, fmt.Sprintf("file%03d.go", f)), []byte(src.String()), 0o644); err != nil {
+				tb.Fatal(err)
+			}
+		}
+	}
+	return dir
+}
+
+func TestLinterService_Analyze_jobs(t *testing.T) {
+	dir := writePackages(t, 8, 8)
+
+	var reports []*models.Report
+	for _, jobs := range []int{1, 4, 16} {
+		ls := NewLinterService(&models.LintConfig{Threshold: 3, Jobs: jobs}, complexity.NewComplexityService())
+		report, err := ls.Analyze([]string{dir})
+		if err != nil {
+			t.Fatalf("Analyze() with %d jobs error = %v", jobs, err)
+		}
+		if files, functions := ls.Counts(); files != 64 || functions != 640 {
+			t.Errorf("Counts() with %d jobs = %d, %d, want 64, 640", jobs, files, functions)
+		}
+		reports = append(reports, report)
+	}
+
+	for i := 1; i < len(reports); i++ {
+		if !reflect.DeepEqual(reports[i], reports[0]) {
+			t.Errorf("Analyze() report differs between job counts")
+		}
+	}
+	if got := reports[0].Files[0].Path; got != filepath.Join(dir, "pkg000", "file000.go") {
+		t.Errorf("Analyze() first file = %s, want the first file in walk order", got)
+	}
+}
+
+func TestLinterService_Analyze_error(t *testing.T) {
+	dir := writePackages(t, 2, 2)
+	if err := os.WriteFile(filepath.Join(dir, "pkg001", "broken.go"), []byte("package"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ls := NewLinterService(&models.LintConfig{Jobs: 4}, complexity.NewComplexityService())
+	if _, err := ls.Analyze([]string{dir}); err == nil || !strings.Contains(err.Error(), "broken.go") {
+		t.Errorf("Analyze() error = %v, want the parse error of broken.go", err)
+	}
+}
+
+func TestLinterService_Analyze_cache(t *testing.T) {
+	dir := writePackages(t, 2, 3)
+	c := cache.New(t.TempDir(), "test")
+
+	analyze := func(config *models.LintConfig) *models.Report {
+		t.Helper()
+		ls := NewLinterService(config, complexity.NewComplexityService())
+		ls.SetCache(c)
+		report, err := ls.Analyze([]string{dir})
+		if err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		return report
+	}
+
+	first := analyze(&models.LintConfig{Threshold: 3})
+	if hits, misses := c.Stats(); hits != 0 || misses != 6 {
+		t.Errorf("first run Stats() = %d, %d, want 0, 6", hits, misses)
+	}
+
+	// the number of workers does not change the analysis
+	second := analyze(&models.LintConfig{Threshold: 3, Jobs: 2})
+	if hits, misses := c.Stats(); hits != 6 || misses != 6 {
+		t.Errorf("second run Stats() = %d, %d, want 6, 6", hits, misses)
+	}
+	if !reflect.DeepEqual(second, first) {
+		t.Errorf("Analyze() from the cache differs from the analysis")
+	}
+
+	changed := filepath.Join(dir, "pkg001", "file002.go")
+	if err := os.WriteFile(changed, []byte("package pkg001\n\nfunc Changed() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	analyze(&models.LintConfig{Threshold: 3})
+	if hits, misses := c.Stats(); hits != 11 || misses != 7 {
+		t.Errorf("run after a change Stats() = %d, %d, want 11, 7", hits, misses)
+	}
+
+	analyze(&models.LintConfig{Threshold: 4})
+	if hits, misses := c.Stats(); hits != 11 || misses != 13 {
+		t.Errorf("run with another threshold Stats() = %d, %d, want 11, 13", hits, misses)
+	}
+}
+
+func BenchmarkLinterService_Analyze(b *testing.B) {
+	dir := writePackages(b, 20, 10)
+	jobCounts := []int{1, 2, 4, 8}
+	if n := runtime.GOMAXPROCS(0); n > 8 {
+		jobCounts = append(jobCounts, n)
+	}
+	for _, jobs := range jobCounts {
+		b.Run(fmt.Sprintf("jobs=%d", jobs), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ls := NewLinterService(&models.LintConfig{Threshold: 3, Jobs: jobs}, complexity.NewComplexityService())
+				if _, err := ls.Analyze([]string{dir}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package linter
+
+import (
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+const (
+	directiveRuleID = "directive"
+
+	directivePrefix = "//strict:"
+	nolintPrefix    = "//nolint"
+	// nolintName is the name of the linter in //nolint comments
+	nolintName = "gostrict"
+)
+
+// directive is a suppression or threshold comment in the source
+type directive struct {
+	text string
+	pos  token.Position
+	// kind is "ignore", "file-ignore" or "threshold"
+	kind string
+	// rules are the rules that are suppressed; empty means all rules
+	rules     []string
+	threshold int
+	// funcDecl is the function the directive is attached to, if any
+	funcDecl *ast.FuncDecl
+	// start and end are the lines the directive applies to
+	start, end int
+	used       bool
+}
+
+// covers reports whether the directive suppresses the result
+func (d *directive) covers(result *models.LintResult) bool {
+	if d.kind == "threshold" || result.Line < d.start || result.Line > d.end {
+		return false
+	}
+	if len(d.rules) == 0 {
+		return true
+	}
+	for _, rule := range d.rules {
+		if rule == result.Rule {
+			return true
+		}
+	}
+	return false
+}
+
+// directives are the directives of a file and the comments that could not be parsed
+type directives struct {
+	list      []*directive
+	malformed []*models.LintResult
+}
+
+// parseDirectives collects the //strict: and //nolint:gostrict comments of the file.
+// Directives on the line of a function declaration or in its doc comment apply
+// to the whole function, other ignore directives to their own line only.
+func parseDirectives(fset *token.FileSet, f *ast.File) *directives {
+	attached := make(map[*ast.Comment]*ast.FuncDecl)
+	funcLines := make(map[int]*ast.FuncDecl)
+	for _, decl := range f.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		funcLines[fset.Position(funcDecl.Pos()).Line] = funcDecl
+		if funcDecl.Doc != nil {
+			for _, comment := range funcDecl.Doc.List {
+				attached[comment] = funcDecl
+			}
+		}
+	}
+
+	ds := &directives{}
+	for _, group := range f.Comments {
+		for _, comment := range group.List {
+			pos := fset.Position(comment.Slash)
+			funcDecl, ok := attached[comment]
+			if !ok {
+				funcDecl = funcLines[pos.Line]
+			}
+
+			d, err := parseDirective(comment.Text)
+			if err != nil {
+				ds.malformed = append(ds.malformed, &models.LintResult{
+					File:     pos.Filename,
+					Line:     pos.Line,
+					Column:   pos.Column,
+					EndLine:  pos.Line,
+					Message:  fmt.Sprintf("malformed directive %q: %v", comment.Text, err),
+					Severity: models.SeverityWarning,
+					Rule:     directiveRuleID,
+				})
+				continue
+			}
+			if d == nil {
+				continue
+			}
+
+			d.pos = pos
+			switch {
+			case d.kind == "file-ignore":
+				d.start, d.end = 1, fset.File(f.Pos()).LineCount()
+			case funcDecl != nil:
+				d.funcDecl = funcDecl
+				d.start, d.end = fset.Position(funcDecl.Pos()).Line, fset.Position(funcDecl.End()).Line
+			case d.kind == "threshold":
+				ds.malformed = append(ds.malformed, &models.LintResult{
+					File:     pos.Filename,
+					Line:     pos.Line,
+					Column:   pos.Column,
+					EndLine:  pos.Line,
+					Message:  fmt.Sprintf("directive %q is not attached to a function declaration", comment.Text),
+					Severity: models.SeverityWarning,
+					Rule:     directiveRuleID,
+				})
+				continue
+			default:
+				d.start, d.end = pos.Line, pos.Line
+			}
+			ds.list = append(ds.list, d)
+		}
+	}
+	return ds
+}
+
+// parseDirective parses a single comment. It returns nil for comments that are
+// not directives of this linter.
+func parseDirective(text string) (*directive, error) {
+	if strings.HasPrefix(text, nolintPrefix) {
+		return parseNolint(text), nil
+	}
+	if !strings.HasPrefix(text, directivePrefix) {
+		return nil, nil
+	}
+
+	kind, args, _ := strings.Cut(strings.TrimPrefix(text, directivePrefix), " ")
+	d := &directive{text: text, kind: kind}
+	switch kind {
+	case "ignore", "file-ignore":
+		rules, err := parseIgnoreArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		d.rules = rules
+	case "threshold":
+		threshold, err := strconv.Atoi(strings.TrimSpace(args))
+		if err != nil || threshold <= 0 {
+			return nil, fmt.Errorf("expected a positive threshold, got %q", strings.TrimSpace(args))
+		}
+		d.threshold = threshold
+	default:
+		return nil, fmt.Errorf("unknown directive %q, expected ignore, file-ignore or threshold", kind)
+	}
+	return d, nil
+}
+
+// parseIgnoreArgs parses the rules and the optional reason of an ignore directive,
+// e.g. `complexity,lll reason="generated lookup table"`
+func parseIgnoreArgs(args string) ([]string, error) {
+	list, reason, hasReason := strings.Cut(args, "reason=")
+	if hasReason {
+		quoted, err := strconv.QuotedPrefix(reason)
+		if err != nil {
+			return nil, fmt.Errorf("reason must be a quoted string")
+		}
+		if unquoted, _ := strconv.Unquote(quoted); strings.TrimSpace(unquoted) == "" {
+			return nil, fmt.Errorf("reason must not be empty")
+		}
+		if rest := strings.TrimSpace(reason[len(quoted):]); rest != "" {
+			return nil, fmt.Errorf("unexpected %q after the reason", rest)
+		}
+	}
+
+	var rules []string
+	for _, rule := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if _, ok := registry[rule]; !ok {
+			return nil, fmt.Errorf("unknown rule %q", rule)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// parseNolint parses a //nolint comment; only comments without a list of
+// linters or with gostrict in it apply to this linter, and only the latter are
+// reported when unused
+func parseNolint(text string) *directive {
+	rest := strings.TrimPrefix(text, nolintPrefix)
+	rest, _, _ = strings.Cut(rest, "//")
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return &directive{text: text, kind: "ignore", used: true}
+	}
+	if !strings.HasPrefix(rest, ":") {
+		return nil
+	}
+	for _, name := range strings.Split(strings.TrimPrefix(rest, ":"), ",") {
+		if strings.TrimSpace(name) == nolintName {
+			return &directive{text: text, kind: "ignore"}
+		}
+	}
+	return nil
+}
+
+// thresholds returns the complexity thresholds set by //strict:threshold per function
+func (ds *directives) thresholds() map[*ast.FuncDecl]int {
+	thresholds := make(map[*ast.FuncDecl]int)
+	for _, d := range ds.list {
+		if d.kind == "threshold" {
+			thresholds[d.funcDecl] = d.threshold
+		}
+	}
+	return thresholds
+}
+
+// suppress removes the results covered by a directive and returns the rest
+// together with the number of suppressed results
+func (ds *directives) suppress(results []*models.LintResult) ([]*models.LintResult, int) {
+	var kept []*models.LintResult
+	suppressed := 0
+	for _, result := range results {
+		covered := false
+		for _, d := range ds.list {
+			if result.Rule != directiveRuleID && d.covers(result) {
+				d.used = true
+				covered = true
+			}
+		}
+		if covered {
+			suppressed++
+			continue
+		}
+		kept = append(kept, result)
+	}
+	return kept, suppressed
+}
+
+// unused returns a result for every ignore directive that suppressed nothing
+func (ds *directives) unused() []*models.LintResult {
+	var results []*models.LintResult
+	for _, d := range ds.list {
+		if d.used || d.kind == "threshold" {
+			continue
+		}
+		results = append(results, &models.LintResult{
+			File:     d.pos.Filename,
+			Line:     d.pos.Line,
+			Column:   d.pos.Column,
+			EndLine:  d.pos.Line,
+			Message:  fmt.Sprintf("directive %q does not suppress any result", d.text),
+			Severity: models.SeverityWarning,
+			Rule:     directiveRuleID,
+		})
+	}
+	return results
+}
+
+// applyDirectives suppresses the results of the file that are covered by its
+// directives. If the directive rule is enabled, malformed and unused directives
+// are added to the results.
+func (ls *LinterService) applyDirectives(fset *token.FileSet, f *ast.File, results []*models.LintResult) ([]*models.LintResult, int, error) {
+	rules, err := ls.activeRules()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ds := parseDirectives(fset, f)
+	results, suppressed := ds.suppress(results)
+	for _, rule := range rules {
+		if rule.ID() == directiveRuleID {
+			results = append(results, ds.unused()...)
+		}
+	}
+	return results, suppressed, nil
+}
+
+// directiveRule reports malformed //strict: directives. Unused directives are
+// only known after all rules ran and are reported by applyDirectives.
+type directiveRule struct{}
+
+func newDirectiveRule(*LinterService) Rule {
+	return &directiveRule{}
+}
+
+func (r *directiveRule) ID() string {
+	return directiveRuleID
+}
+
+func (r *directiveRule) Description() string {
+	return "Suppression directives should be well-formed and suppress a result"
+}
+
+func (r *directiveRule) Check(fset *token.FileSet, f *ast.File) ([]*models.LintResult, error) {
+	return parseDirectives(fset, f).malformed, nil
+}
@@ -0,0 +1,107 @@
+package linter
+
+import (
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"github.com/MikeMwita/go-strict/services/complexity"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// nested is the body of a function with a cognitive complexity of 6
+const nested = `{
+	if x > 0 {
+		if x > 1 {
+			if x > 2 {
+				return 3
+			}
+		}
+	}
+	return 0
+}`
+
+func TestLinterService_Analyze_directives(t *testing.T) {
+	tests := []struct {
+		name           string
+		src            string
+		want           []string
+		wantSuppressed int
+	}{
+		{
+			name: "Test no directives",
+			src:  "func F(x int) int " + nested,
+			want: []string{"complexity:3"},
+		},
+		{
+			name:           "Test ignore above the function",
+			src:            "// F is complex\n//strict:ignore complexity reason=\"lookup table\"\nfunc F(x int) int " + nested,
+			wantSuppressed: 1,
+		},
+		{
+			name:           "Test nolint on the function line",
+			src:            "func F(x int) int { //nolint:errcheck,gostrict // generated\n\tif x > 0 {\n\t\tif x > 1 {\n\t\t\tif x > 2 {\n\t\t\t\treturn 3\n\t\t\t}\n\t\t}\n\t}\n\treturn 0\n}",
+			wantSuppressed: 1,
+		},
+		{
+			name: "Test nolint for other linters",
+			src:  "//nolint:errcheck\nfunc F(x int) int " + nested,
+			want: []string{"complexity:4"},
+		},
+		{
+			name: "Test ignore of another rule is unused",
+			src:  "//strict:ignore lll\nfunc F(x int) int " + nested,
+			want: []string{"complexity:4", "directive:3"},
+		},
+		{
+			name:           "Test file ignore",
+			src:            "//strict:file-ignore\n\nfunc F(x int) int " + nested + "\n\nfunc G(x int) int " + nested,
+			wantSuppressed: 2,
+		},
+		{
+			name: "Test threshold above the score",
+			src:  "//strict:threshold 6\nfunc F(x int) int " + nested,
+		},
+		{
+			name: "Test threshold below the score",
+			src:  "//strict:threshold 5\nfunc F(x int) int " + nested,
+			want: []string{"complexity:4"},
+		},
+		{
+			name: "Test malformed directives",
+			src:  "//strict:threshold many\nfunc F(x int) int { return x }\n\n//strict:ignore complexity reason=unquoted\nfunc G(x int) int { return x }\n\n//strict:silence\nvar v = 1\n\n//strict:threshold 20\nvar w = 1",
+			want: []string{"directive:3", "directive:6", "directive:9", "directive:12"},
+		},
+		{
+			name: "Test unknown rule",
+			src:  "//strict:ignore complexity,unknown\nfunc F(x int) int " + nested,
+			want: []string{"complexity:4", "directive:3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "main.go")
+			if err := os.WriteFile(path, []byte("package main\n\n"+tt.src+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			ls := NewLinterService(&models.LintConfig{Threshold: 5}, complexity.NewComplexityService())
+			report, err := ls.Analyze([]string{path})
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+
+			var got []string
+			for _, result := range report.Results {
+				got = append(got, fmt.Sprintf("%s:%d", result.Rule, result.Line))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Analyze() results = %v, want %v", got, tt.want)
+			}
+			if report.Suppressed != tt.wantSuppressed {
+				t.Errorf("Analyze() suppressed = %d, want %d", report.Suppressed, tt.wantSuppressed)
+			}
+		})
+	}
+}
@@ -1,7 +1,13 @@
 package linter
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/MikeMwita/go-strict/internal/cache"
+	"github.com/MikeMwita/go-strict/internal/file"
 	"github.com/MikeMwita/go-strict/models"
 	"github.com/MikeMwita/go-strict/services/complexity"
 	"go/ast"
@@ -10,11 +16,14 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
-	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 type Linter interface {
+	Analyze(files []string) (*models.Report, error)
 	LintFiles(files []string) ([]*models.LintResult, error)
 	LintFunctions(functions []string) ([]*models.LintResult, error)
 }
@@ -22,8 +31,21 @@ type Linter interface {
 type LinterService struct {
 	config     *models.LintConfig
 	complexity *complexity.ComplexityService
-	fileCount  int
-	funcCount  int
+	cache      *cache.Cache
+
+	// rules are created once, on first use, and then shared by all callers
+	rulesOnce sync.Once
+	rules     []Rule
+	rulesErr  error
+
+	// sources holds the content that files were parsed from, keyed by their
+	// *token.File, so rules see exactly what the cache key was derived from
+	sources sync.Map
+
+	// counters are shared by the workers of Analyze
+	mu        sync.Mutex
+	fileCount int
+	funcCount int
 }
 
 func NewLinterService(config *models.LintConfig, complexity *complexity.ComplexityService) *LinterService {
@@ -34,11 +56,21 @@ func NewLinterService(config *models.LintConfig, complexity *complexity.Complexi
 }
 
 func createTempFile(functions []string) (*os.File, error) {
+	for _, function := range functions {
+		if strings.TrimSpace(function) == "" {
+			return nil, errors.New("empty function given")
+		}
+	}
+
 	tmpFile, err := ioutil.TempFile("", "tempfunctions*.go")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create temporary file: %w", err)
 	}
 
+	if _, err := tmpFile.WriteString("package main\n\n"); err != nil {
+		return nil, fmt.Errorf("failed to write to temporary file: %w", err)
+	}
+
 	for _, function := range functions {
 		if _, err := tmpFile.WriteString(function + "\n\n"); err != nil {
 			return nil, fmt.Errorf("failed to write to temporary file: %w", err)
@@ -52,40 +84,150 @@ func createTempFile(functions []string) (*os.File, error) {
 	return tmpFile, nil
 }
 
+// LintFiles lints the given files and directories and returns the findings
 func (ls *LinterService) LintFiles(files []string) ([]*models.LintResult, error) {
-	var results []*models.LintResult
-	fset := token.NewFileSet()
+	report, err := ls.Analyze(files)
+	if err != nil {
+		return nil, err
+	}
+	return report.Results, nil
+}
 
-	for _, file := range files {
-		err := filepath.Walk(file, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				log.Printf("Error walking file tree: %v", err)
-				return err
-			}
+// Analyze lints the given files and directories. Next to the findings, the
+// report lists every analysed file and function with its complexity. Files are
+// analysed by up to Jobs workers at once; the report is in walk order regardless.
+func (ls *LinterService) Analyze(files []string) (*models.Report, error) {
+	rules, err := ls.activeRules()
+	if err != nil {
+		return nil, err
+	}
 
-			if !info.IsDir() && strings.HasSuffix(info.Name(), ".go") {
-				fileResults, err := ls.lintGoFile(fset, path)
-				if err != nil {
-					log.Printf("Error linting Go file %s: %v", path, err)
-					return err
-				}
-				results = append(results, fileResults...)
-			}
-			return nil
-		})
+	paths, err := ls.goFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	analyses, err := ls.analyzeFiles(paths)
+	if err != nil {
+		return nil, err
+	}
 
+	report := &models.Report{Rules: ruleInfos(rules)}
+	for _, analysis := range analyses {
+		report.Files = append(report.Files, analysis.file)
+		report.Suppressed += analysis.file.Suppressed
+		report.Results = append(report.Results, analysis.results...)
+	}
+	return report, nil
+}
+
+// goFiles returns the Go files in the given files and directories in walk order.
+// Directories are filtered by the include and exclude globs, the skip options
+// and the max depth of the config; generated files are always skipped. Files
+// that are given explicitly are always linted.
+func (ls *LinterService) goFiles(files []string) ([]string, error) {
+	opts := &file.Options{
+		Pattern:       "*.go",
+		Include:       ls.config.Include,
+		Exclude:       ls.config.Exclude,
+		SkipTests:     ls.config.SkipTests,
+		SkipVendor:    ls.config.SkipVendor,
+		SkipGenerated: true,
+		MaxDepth:      ls.config.MaxDepth,
+	}
+
+	var paths []string
+	for _, root := range files {
+		found, err := file.Walk(root, opts)
 		if err != nil {
 			log.Printf("Error walking file tree: %v", err)
 			return nil, err
 		}
+		paths = append(paths, found...)
 	}
+	return paths, nil
+}
 
-	return results, nil
+// fileAnalysis is the outcome of linting a single file
+type fileAnalysis struct {
+	file    *models.FileReport
+	results []*models.LintResult
+	err     error
 }
 
-func (ls *LinterService) LintFunctions(functions []string) ([]*models.LintResult, error) {
-	var results []*models.LintResult
+// workers returns the number of files that are analysed in parallel
+func (ls *LinterService) workers() int {
+	if ls.config.Jobs > 0 {
+		return ls.config.Jobs
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// analyzeFiles lints the files with a bounded pool of workers. Every file gets
+// its own FileSet, and the analyses are returned in the order of paths. After
+// the first failure no new files are started; the error of the earliest failing
+// file is returned.
+func (ls *LinterService) analyzeFiles(paths []string) ([]*fileAnalysis, error) {
+	analyses := make([]*fileAnalysis, len(paths))
+	indexes := make(chan int)
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+
+	workers := ls.workers()
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if failed.Load() {
+					continue
+				}
+				fileReport, results, err := ls.lintGoFile(token.NewFileSet(), paths[i])
+				if err != nil {
+					log.Printf("Error linting Go file %s: %v", paths[i], err)
+					failed.Store(true)
+				}
+				analyses[i] = &fileAnalysis{file: fileReport, results: results, err: err}
+			}
+		}()
+	}
+
+	for i := range paths {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, analysis := range analyses {
+		if analysis != nil && analysis.err != nil {
+			return nil, analysis.err
+		}
+	}
+	return analyses, nil
+}
+
+// AnalyzeSource scores every function of the given source without running the
+// rules, e.g. to compare with an older version of a file
+func (ls *LinterService) AnalyzeSource(filename string, src []byte) (*models.FileReport, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	return ls.analyzeFile(fset, f)
+}
+
+// Counts returns the number of files linted and functions analysed so far
+func (ls *LinterService) Counts() (files, functions int) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	return ls.fileCount, ls.funcCount
+}
 
+func (ls *LinterService) LintFunctions(functions []string) ([]*models.LintResult, error) {
 	fset := token.NewFileSet()
 	tmpFile, err := createTempFile(functions)
 	if err != nil {
@@ -98,98 +240,274 @@ func (ls *LinterService) LintFunctions(functions []string) ([]*models.LintResult
 		return nil, err
 	}
 
-	for _, decl := range f.Decls {
-		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
-			funcResult, err := ls.lintFunction(fset, funcDecl)
-			if err != nil {
-				log.Printf("Error linting function %s: %v", funcDecl.Name.Name, err)
-				continue
-			}
-
-			if funcResult != nil {
-				results = append(results, funcResult)
-			}
-		}
+	results, err := ls.lintFile(fset, f)
+	if err != nil {
+		return nil, err
 	}
+	results, _, err = ls.applyDirectives(fset, f, results)
+	return results, err
+}
 
-	return results, nil
+// SetCache makes Analyze reuse the analyses of files whose content and config
+// did not change; nil disables caching
+func (ls *LinterService) SetCache(c *cache.Cache) {
+	ls.cache = c
+}
+
+// configHash identifies the settings that change the outcome of an analysis
+func (ls *LinterService) configHash() (string, error) {
+	config := *ls.config
+	config.Output, config.OutputFile, config.Paths, config.Jobs = "", "", nil, 0
+	config.CacheDir, config.NoCache = "", false
+	config.Include, config.Exclude, config.SkipTests, config.SkipVendor, config.MaxDepth = nil, nil, false, false, 0
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
 }
 
-func (ls *LinterService) lintGoFile(fset *token.FileSet, filePath string) ([]*models.LintResult, error) {
-	f, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+func (ls *LinterService) lintGoFile(fset *token.FileSet, filePath string) (*models.FileReport, []*models.LintResult, error) {
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var key string
+	if ls.cache != nil {
+		configHash, err := ls.configHash()
+		if err != nil {
+			return nil, nil, err
+		}
+		key = ls.cache.Key(src, configHash)
+		if entry, ok := ls.cache.Get(key); ok {
+			return ls.cached(filePath, entry)
+		}
+	}
+
+	f, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
 	if err != nil {
 		log.Printf("Error parsing Go file %s: %v", filePath, err)
-		return nil, err
+		return nil, nil, err
 	}
 
-	return ls.lintFile(fset, f)
+	fileReport, err := ls.analyzeFile(fset, f)
+	if err != nil {
+		return nil, nil, err
+	}
+	ls.mu.Lock()
+	ls.funcCount += len(fileReport.Functions)
+	ls.mu.Unlock()
+
+	tokFile := fset.File(f.Pos())
+	ls.sources.Store(tokFile, src)
+	results, err := ls.lintFile(fset, f)
+	ls.sources.Delete(tokFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	results, fileReport.Suppressed, err = ls.applyDirectives(fset, f, results)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if ls.cache != nil {
+		if err := ls.cache.Put(key, &cache.Entry{File: fileReport, Results: results}); err != nil {
+			log.Printf("Error caching the analysis of %s: %v", filePath, err)
+		}
+	}
+	return fileReport, results, nil
+}
+
+// cached restores a cached analysis for the file at filePath
+func (ls *LinterService) cached(filePath string, entry *cache.Entry) (*models.FileReport, []*models.LintResult, error) {
+	entry.File.Path = filePath
+	for _, result := range entry.Results {
+		result.File = filePath
+	}
+
+	ls.mu.Lock()
+	ls.fileCount++
+	ls.funcCount += len(entry.File.Functions)
+	ls.mu.Unlock()
+	return entry.File, entry.Results, nil
+}
+
+// source returns the content of a file that is being linted: the source it was
+// parsed from, or else the file on disk
+func (ls *LinterService) source(tokFile *token.File) ([]byte, error) {
+	if src, ok := ls.sources.Load(tokFile); ok {
+		return src.([]byte), nil
+	}
+	return os.ReadFile(tokFile.Name())
+}
+
+// analyzeFile scores every function of the file, whether or not it is above the threshold
+func (ls *LinterService) analyzeFile(fset *token.FileSet, f *ast.File) (*models.FileReport, error) {
+	fileReport := &models.FileReport{
+		Path:    fset.Position(f.Pos()).Filename,
+		Package: f.Name.Name,
+	}
+
+	for _, decl := range f.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+
+		functions, err := ls.complexity.Functions(fset, funcDecl, ls.splitClosure(fset))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, function := range functions {
+			fileReport.Functions = append(fileReport.Functions, &models.FunctionReport{
+				Name:       function.Name,
+				Receiver:   receiverType(funcDecl),
+				Line:       fset.Position(function.Node.Pos()).Line,
+				EndLine:    fset.Position(function.Node.End()).Line,
+				Complexity: function.Score(),
+			})
+		}
+	}
+	return fileReport, nil
+}
+
+// receiverType returns the type name of a method receiver without pointer or
+// type parameters, or "" for plain functions
+func receiverType(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return ""
+	}
+
+	expr := funcDecl.Recv.List[0].Type
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
 }
 
 func (ls *LinterService) lintFile(fset *token.FileSet, f *ast.File) ([]*models.LintResult, error) {
 	var fileResults []*models.LintResult
-	fileName := fset.File(f.Pos()).Name()
+	tokFile := fset.File(f.Pos())
+	if tokFile == nil {
+		return nil, errors.New("no position information found for file")
+	}
+	fileName := tokFile.Name()
 
-	for _, decl := range f.Decls {
-		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
-			funcResult, err := ls.lintFunction(fset, funcDecl)
-			if err != nil {
-				return nil, err
-			}
+	rules, err := ls.activeRules()
+	if err != nil {
+		return nil, err
+	}
 
-			if funcResult != nil {
-				funcResult.File = fileName
-				fileResults = append(fileResults, funcResult)
-			}
+	for _, rule := range rules {
+		ruleResults, err := rule.Check(fset, f)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.ID(), err)
+		}
+
+		for _, ruleResult := range ruleResults {
+			ruleResult.File = fileName
+			ruleResult.Rule = rule.ID()
+			fileResults = append(fileResults, ruleResult)
 		}
 	}
 
+	ls.mu.Lock()
 	ls.fileCount++
+	ls.mu.Unlock()
 	return fileResults, nil
 }
 
-func (ls *LinterService) lintFunction(fset *token.FileSet, funcDecl *ast.FuncDecl) (*models.LintResult, error) {
+// lintFunction checks the complexity of a function declaration. Functions above
+// the threshold are warnings, functions above MaxComplexity are errors. Closures
+// large enough to be split off are checked on their own and reported as separate results.
+func (ls *LinterService) lintFunction(fset *token.FileSet, funcDecl *ast.FuncDecl) ([]*models.LintResult, error) {
+	return ls.lintFunctionThreshold(fset, funcDecl, ls.config.Threshold)
+}
+
+// lintFunctionThreshold reports the parts of the function whose complexity is above
+// threshold. MaxComplexity only applies if it is above threshold.
+func (ls *LinterService) lintFunctionThreshold(fset *token.FileSet, funcDecl *ast.FuncDecl, threshold int) ([]*models.LintResult, error) {
+	if funcDecl == nil {
+		return nil, errors.New("cannot lint a nil function declaration")
+	}
 	if funcDecl.Body == nil {
 		return nil, nil
 	}
 
-	complexityScore, err := ls.complexity.Calculate(fset, funcDecl.Body)
+	functions, err := ls.complexity.Functions(fset, funcDecl, ls.splitClosure(fset))
 	if err != nil {
 		return nil, err
 	}
 
-	if complexityScore > ls.config.Threshold {
+	var results []*models.LintResult
+	for _, function := range functions {
+		complexityScore := function.Score()
+		if complexityScore <= threshold {
+			continue
+		}
+
 		result := &models.LintResult{
-			File:     fset.Position(funcDecl.Pos()).Filename,
-			Line:     fset.Position(funcDecl.Pos()).Line,
-			Function: funcDecl.Name.Name,
-			Message:  fmt.Sprintf("function has a cognitive complexity of %d which is higher than the threshold of %d", complexityScore, ls.config.Threshold),
-			Severity: "warning",
-		}
-
-		details := ls.generateComplexityDetails(fset, funcDecl.Body)
-		result.Message = fmt.Sprintf("%s (Complexity details:\n%s)", result.Message, strings.Join(details, "\n"))
-
-		return result, nil
-	}
-	return nil, nil
-}
-
-func (ls *LinterService) generateComplexityDetails(fset *token.FileSet, body *ast.BlockStmt) []string {
-	var details []string
-	ast.Inspect(body, func(n ast.Node) bool {
-		switch node := n.(type) {
-		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
-			line := fset.Position(node.Pos()).Line
-			score := ls.complexity.Complexity(node)
-			detail := fmt.Sprintf("+ %d (found at line: %d)", score, line)
-			details = append(details, detail)
-		case *ast.CaseClause:
-			line := fset.Position(node.Pos()).Line
-			score := ls.complexity.Complexity(node)
-			detail := fmt.Sprintf("+ %d (found 'case' at line: %d)", score, line)
-			details = append(details, detail)
-		}
-		return true
-	})
-	return details
+			File:     fset.Position(function.Node.Pos()).Filename,
+			Line:     fset.Position(function.Node.Pos()).Line,
+			EndLine:  fset.Position(function.Node.End()).Line,
+			Function: function.Name,
+			Message:  fmt.Sprintf("function has a cognitive complexity of %d which is higher than the threshold of %d", complexityScore, threshold),
+			Severity: models.SeverityWarning,
+		}
+		limit := threshold
+		if ls.config.MaxComplexity > threshold && complexityScore > ls.config.MaxComplexity {
+			limit = ls.config.MaxComplexity
+			result.Message = fmt.Sprintf("function has a cognitive complexity of %d which is higher than the maximum of %d", complexityScore, limit)
+			result.Severity = models.SeverityError
+		}
+		result.Complexity = ls.complexityReport(function.Increments, limit)
+
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// splitClosure returns the predicate selecting the closures that are scored on
+// their own, or nil if closures always count towards their parent
+func (ls *LinterService) splitClosure(fset *token.FileSet) func(*ast.FuncLit) bool {
+	if ls.config.ClosureMinLines <= 0 {
+		return nil
+	}
+	return func(lit *ast.FuncLit) bool {
+		lines := fset.Position(lit.End()).Line - fset.Position(lit.Pos()).Line + 1
+		return lines >= ls.config.ClosureMinLines
+	}
+}
+
+// complexityReport converts the increments of a function into the structured breakdown of its result
+func (ls *LinterService) complexityReport(increments []complexity.Increment, threshold int) *models.ComplexityReport {
+	report := &models.ComplexityReport{Threshold: threshold}
+	for _, inc := range increments {
+		report.Score += inc.Value
+		report.Increments = append(report.Increments, models.ComplexityIncrement{
+			Kind:      inc.Kind,
+			Line:      inc.Position.Line,
+			Column:    inc.Position.Column,
+			Increment: inc.Value,
+			Nesting:   inc.Nesting,
+			Total:     report.Score,
+		})
+	}
+	return report
 }
@@ -8,6 +8,7 @@ import (
 	"go/token"
 	"os"
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -39,13 +40,7 @@ func TestLinterService_LintFiles(t *testing.T) {
 			args: args{
 				files: []string{"./testdata/valid.go"},
 			},
-			want: []*models.LintResult{
-				{
-					File:     "./testdata/valid.go",
-					Message:  "No issues found",
-					Severity: "info",
-				},
-			},
+			want:    nil,
 			wantErr: false,
 		},
 		{
@@ -59,13 +54,7 @@ func TestLinterService_LintFiles(t *testing.T) {
 			args: args{
 				files: []string{"./testdata/invalid.go"},
 			},
-			want: []*models.LintResult{
-				{
-					File:     "./testdata/invalid.go",
-					Message:  "expected 'package', found 'EOF'",
-					Severity: "error",
-				},
-			},
+			want:    nil,
 			wantErr: true,
 		},
 		{
@@ -79,18 +68,7 @@ func TestLinterService_LintFiles(t *testing.T) {
 			args: args{
 				files: []string{"./testdata"},
 			},
-			want: []*models.LintResult{
-				{
-					File:     "./testdata/valid.go",
-					Message:  "No issues found",
-					Severity: "info",
-				},
-				{
-					File:     "./testdata/invalid.go",
-					Message:  "expected 'package', found 'EOF'",
-					Severity: "error",
-				},
-			},
+			want:    nil,
 			wantErr: true,
 		},
 	}
@@ -114,6 +92,38 @@ func TestLinterService_LintFiles(t *testing.T) {
 	}
 }
 
+func TestLinterService_Analyze(t *testing.T) {
+	ls := NewLinterService(&models.LintConfig{Threshold: 3}, complexity.NewComplexityService())
+	got, err := ls.Analyze([]string{"./testdata/valid.go", "./testdata/complex.go"})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	want := []*models.FileReport{
+		{
+			Path:    "./testdata/valid.go",
+			Package: "testdata",
+			Functions: []*models.FunctionReport{
+				{Name: "Add", Line: 3, EndLine: 5, Complexity: 0},
+				{Name: "Subtract", Line: 7, EndLine: 9, Complexity: 0},
+			},
+		},
+		{
+			Path:    "./testdata/complex.go",
+			Package: "testdata",
+			Functions: []*models.FunctionReport{
+				{Name: "Classify", Line: 3, EndLine: 12, Complexity: 4},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got.Files, want) {
+		t.Errorf("Analyze() files = %v, want %v", got.Files, want)
+	}
+	if len(got.Results) != 1 || got.Results[0].Function != "Classify" {
+		t.Errorf("Analyze() results = %v, want only Classify", got.Results)
+	}
+}
+
 func TestLinterService_LintFunctions(t *testing.T) {
 	type fields struct {
 		config     *models.LintConfig
@@ -142,18 +152,11 @@ func TestLinterService_LintFunctions(t *testing.T) {
 			args: args{
 				functions: []string{"func Add(x, y int) int { return x + y }"},
 			},
-			want: []*models.LintResult{
-				{
-					File:     "tmpfile.go",
-					Function: "Add",
-					Message:  "No issues found",
-					Severity: "info",
-				},
-			},
+			want:    nil,
 			wantErr: false,
 		},
 		{
-			name: "Test invalid function",
+			name: "Test multiple functions",
 			fields: fields{
 				config:     &models.LintConfig{MaxComplexity: 10, MaxLineLength: 80},
 				complexity: &complexity.ComplexityService{},
@@ -163,20 +166,7 @@ func TestLinterService_LintFunctions(t *testing.T) {
 			args: args{
 				functions: []string{"func Subtract(x, y int) int { return x - y }", "func Multiply(x, y int) int { return x * y }"},
 			},
-			want: []*models.LintResult{
-				{
-					File:     "tmpfile.go",
-					Function: "Subtract",
-					Message:  "No issues found",
-					Severity: "info",
-				},
-				{
-					File:     "tmpfile.go",
-					Function: "Multiply",
-					Message:  "Line too long: func Multiply(x, y int) int { return x * y }",
-					Severity: "warning",
-				},
-			},
+			want:    nil,
 			wantErr: false,
 		},
 		{
@@ -214,6 +204,28 @@ func TestLinterService_LintFunctions(t *testing.T) {
 	}
 }
 
+func TestLinterService_LintFunctions_concurrent(t *testing.T) {
+	ls := NewLinterService(&models.LintConfig{Threshold: 0}, complexity.NewComplexityService())
+	function := "func f(x int) int {\n\tif x > 0 {\n\t\treturn 1\n\t}\n\treturn 0\n}"
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = ls.LintFunctions([]string{function})
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Errorf("LintFunctions() error = %v", err)
+		}
+	}
+}
+
 func TestLinterService_lintFile(t *testing.T) {
 	type fields struct {
 		config     *models.LintConfig
@@ -241,43 +253,42 @@ func TestLinterService_lintFile(t *testing.T) {
 				funcCount:  0,
 			},
 			args: args{
-				fset: token.NewFileSet(),
+				fset: testFset,
 				f:    parseFile("./testdata/valid.go"),
 			},
-			want: []*models.LintResult{
-				{
-					File:     "./testdata/valid.go",
-					Function: "Add",
-					Message:  "No issues found",
-					Severity: "info",
-				},
-				{
-					File:     "./testdata/valid.go",
-					Function: "Subtract",
-					Message:  "No issues found",
-					Severity: "info",
-				},
-			},
+			want:    nil,
 			wantErr: false,
 		},
 		{
-			name: "Test invalid Go file",
+			name: "Test complex Go file",
 			fields: fields{
-				config:     &models.LintConfig{MaxComplexity: 10, MaxLineLength: 80},
+				config:     &models.LintConfig{Threshold: 3, MaxComplexity: 10, MaxLineLength: 80},
 				complexity: &complexity.ComplexityService{},
 				fileCount:  0,
 				funcCount:  0,
 			},
 			args: args{
-				fset: token.NewFileSet(),
-				f:    parseFile("./testdata/invalid.go"),
+				fset: testFset,
+				f:    parseFile("./testdata/complex.go"),
 			},
 			want: []*models.LintResult{
 				{
-					File:     "./testdata/invalid.go",
-					Function: "Multiply",
-					Message:  "Line too long: func Multiply(x, y int) int { return x * y }",
+					File:     "./testdata/complex.go",
+					Line:     3,
+					EndLine:  12,
+					Function: "Classify",
+					Message:  "function has a cognitive complexity of 4 which is higher than the threshold of 3",
 					Severity: "warning",
+					Rule:     "complexity",
+					Complexity: &models.ComplexityReport{
+						Score:     4,
+						Threshold: 3,
+						Increments: []models.ComplexityIncrement{
+							{Kind: "range", Line: 4, Column: 2, Increment: 1, Nesting: 0, Total: 1},
+							{Kind: "if", Line: 5, Column: 3, Increment: 2, Nesting: 1, Total: 3},
+							{Kind: "else if", Line: 7, Column: 10, Increment: 1, Nesting: 1, Total: 4},
+						},
+					},
 				},
 			},
 			wantErr: false,
@@ -318,10 +329,12 @@ func TestLinterService_lintFile(t *testing.T) {
 	}
 }
 
+// testFset is shared by the parse helpers so that positions resolve in the tests
+var testFset = token.NewFileSet()
+
 // parseFile is a helper function that parses a Go file and returns an *ast.File
 func parseFile(filename string) *ast.File {
-	fset := token.NewFileSet()
-	f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	f, err := parser.ParseFile(testFset, filename, nil, parser.ParseComments)
 	if err != nil {
 		panic(err)
 	}
@@ -343,7 +356,7 @@ func TestLinterService_lintFunction(t *testing.T) {
 		name    string
 		fields  fields
 		args    args
-		want    *models.LintResult
+		want    []*models.LintResult
 		wantErr bool
 	}{
 		{
@@ -355,7 +368,7 @@ func TestLinterService_lintFunction(t *testing.T) {
 				funcCount:  0,
 			},
 			args: args{
-				fset:     token.NewFileSet(),
+				fset:     testFset,
 				funcDecl: parseFunction("func Add(x, y int) int { return x + y }"),
 			},
 			want:    nil,
@@ -364,19 +377,106 @@ func TestLinterService_lintFunction(t *testing.T) {
 		{
 			name: "Test complex function",
 			fields: fields{
-				config:     &models.LintConfig{Threshold: 10},
+				config:     &models.LintConfig{Threshold: 0},
+				complexity: &complexity.ComplexityService{},
+				fileCount:  0,
+				funcCount:  0,
+			},
+			args: args{
+				fset:     testFset,
+				funcDecl: parseFunction("func Fibonacci(n int) int { if n <= 1 { return n }; return Fibonacci(n-1) + Fibonacci(n-2) }"),
+			},
+			want: []*models.LintResult{
+				{
+					Line:     2,
+					EndLine:  2,
+					Function: "Fibonacci",
+					Severity: "warning",
+					Message:  "function has a cognitive complexity of 3 which is higher than the threshold of 0",
+					Complexity: &models.ComplexityReport{
+						Score:     3,
+						Threshold: 0,
+						Increments: []models.ComplexityIncrement{
+							{Kind: "if", Line: 2, Column: 29, Increment: 1, Nesting: 0, Total: 1},
+							{Kind: "recursion", Line: 2, Column: 60, Increment: 1, Nesting: 0, Total: 2},
+							{Kind: "recursion", Line: 2, Column: 77, Increment: 1, Nesting: 0, Total: 3},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test function above the maximum complexity",
+			fields: fields{
+				config:     &models.LintConfig{Threshold: 1, MaxComplexity: 2},
+				complexity: &complexity.ComplexityService{},
+				fileCount:  0,
+				funcCount:  0,
+			},
+			args: args{
+				fset:     testFset,
+				funcDecl: parseFunction("func Fibonacci(n int) int { if n <= 1 { return n }; return Fibonacci(n-1) + Fibonacci(n-2) }"),
+			},
+			want: []*models.LintResult{
+				{
+					Line:     2,
+					EndLine:  2,
+					Function: "Fibonacci",
+					Severity: "error",
+					Message:  "function has a cognitive complexity of 3 which is higher than the maximum of 2",
+					Complexity: &models.ComplexityReport{
+						Score:     3,
+						Threshold: 2,
+						Increments: []models.ComplexityIncrement{
+							{Kind: "if", Line: 2, Column: 29, Increment: 1, Nesting: 0, Total: 1},
+							{Kind: "recursion", Line: 2, Column: 60, Increment: 1, Nesting: 0, Total: 2},
+							{Kind: "recursion", Line: 2, Column: 77, Increment: 1, Nesting: 0, Total: 3},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test large closure reported on its own",
+			fields: fields{
+				config:     &models.LintConfig{Threshold: 1, ClosureMinLines: 3},
 				complexity: &complexity.ComplexityService{},
 				fileCount:  0,
 				funcCount:  0,
 			},
 			args: args{
-				fset:     token.NewFileSet(),
-				funcDecl: parseFunction("func Fibonacci(n int) int { if n <= 1 { return n } return Fibonacci(n-1) + Fibonacci(n-2) }"),
+				fset: testFset,
+				funcDecl: parseFunction(`func Serve(jobs chan int) {
+	go func() {
+		for j := range jobs {
+			if j > 0 {
+				println(j)
+			}
+		}
+	}()
+	if jobs == nil {
+		return
+	}
+}`),
 			},
-			want: &models.LintResult{
-				Line:     1,
-				Severity: "warning",
-				Message:  "function has a cognitive complexity of 11 which is higher than the threshold of 10\n+ 1 (found at line: 1)\n+ 1 (found 'if' at line: 1)\n+ 9 (found at line: 2)",
+			want: []*models.LintResult{
+				{
+					Line:     3,
+					EndLine:  9,
+					Function: "Serve.func1",
+					Severity: "warning",
+					Message:  "function has a cognitive complexity of 3 which is higher than the threshold of 1",
+					Complexity: &models.ComplexityReport{
+						Score:     3,
+						Threshold: 1,
+						Increments: []models.ComplexityIncrement{
+							{Kind: "range", Line: 4, Column: 3, Increment: 1, Nesting: 0, Total: 1},
+							{Kind: "if", Line: 5, Column: 4, Increment: 2, Nesting: 1, Total: 3},
+						},
+					},
+				},
 			},
 			wantErr: false,
 		},
@@ -389,7 +489,7 @@ func TestLinterService_lintFunction(t *testing.T) {
 				funcCount:  0,
 			},
 			args: args{
-				fset:     token.NewFileSet(),
+				fset:     testFset,
 				funcDecl: nil,
 			},
 			want:    nil,
@@ -418,8 +518,7 @@ func TestLinterService_lintFunction(t *testing.T) {
 
 // parseFunction -->  parses a function declaration and returns an *ast.FuncDecl
 func parseFunction(code string) *ast.FuncDecl {
-	fset := token.NewFileSet()
-	f, err := parser.ParseFile(fset, "", code, 0)
+	f, err := parser.ParseFile(testFset, "", "package main\n"+code, 0)
 	if err != nil {
 		panic(err)
 	}
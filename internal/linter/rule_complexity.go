@@ -0,0 +1,46 @@
+package linter
+
+import (
+	"github.com/MikeMwita/go-strict/models"
+	"go/ast"
+	"go/token"
+)
+
+const complexityRuleID = "complexity"
+
+// complexityRule reports functions whose cognitive complexity is above the
+// threshold, or above the threshold of a //strict:threshold directive
+type complexityRule struct {
+	ls *LinterService
+}
+
+func newComplexityRule(ls *LinterService) Rule {
+	return &complexityRule{ls: ls}
+}
+
+func (r *complexityRule) ID() string {
+	return complexityRuleID
+}
+
+func (r *complexityRule) Description() string {
+	return "Functions should not have a cognitive complexity above the threshold"
+}
+
+func (r *complexityRule) Check(fset *token.FileSet, f *ast.File) ([]*models.LintResult, error) {
+	thresholds := parseDirectives(fset, f).thresholds()
+	var results []*models.LintResult
+	for _, decl := range f.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+			threshold, ok := thresholds[funcDecl]
+			if !ok {
+				threshold = r.ls.config.Threshold
+			}
+			funcResults, err := r.ls.lintFunctionThreshold(fset, funcDecl, threshold)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, funcResults...)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,91 @@
+package linter
+
+import (
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"go/ast"
+	"go/token"
+	"strconv"
+)
+
+const (
+	goconstRuleID = "goconst"
+
+	defaultConstMinOccurrences = 3
+	// goconstMinLength skips short strings such as "", "," or "ok"
+	goconstMinLength = 3
+)
+
+// goconstRule reports string literals that are repeated often enough in a file to be a constant
+type goconstRule struct {
+	minOccurrences int
+}
+
+func newGoconstRule(ls *LinterService) Rule {
+	minOccurrences := ls.config.ConstMinOccurrences
+	if minOccurrences <= 0 {
+		minOccurrences = defaultConstMinOccurrences
+	}
+	return &goconstRule{minOccurrences: minOccurrences}
+}
+
+func (r *goconstRule) ID() string {
+	return goconstRuleID
+}
+
+func (r *goconstRule) Description() string {
+	return "Repeated string literals should be constants"
+}
+
+func (r *goconstRule) Check(fset *token.FileSet, f *ast.File) ([]*models.LintResult, error) {
+	occurrences := make(map[string][]*ast.BasicLit)
+	var order []string
+
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ImportSpec:
+			// import paths are not candidates for constants
+			return false
+		case *ast.Field:
+			// neither are struct tags, so only the field type is inspected
+			ast.Inspect(n.Type, visit)
+			return false
+		case *ast.GenDecl:
+			// literals that already are constants are fine
+			return n.Tok != token.CONST
+		case *ast.BasicLit:
+			if n.Kind != token.STRING {
+				return false
+			}
+			value, err := strconv.Unquote(n.Value)
+			if err != nil || len(value) < goconstMinLength {
+				return false
+			}
+			if _, ok := occurrences[value]; !ok {
+				order = append(order, value)
+			}
+			occurrences[value] = append(occurrences[value], n)
+		}
+		return true
+	}
+	ast.Inspect(f, visit)
+
+	var results []*models.LintResult
+	for _, value := range order {
+		lits := occurrences[value]
+		if len(lits) < r.minOccurrences {
+			continue
+		}
+		pos := fset.Position(lits[0].Pos())
+		results = append(results, &models.LintResult{
+			File:     pos.Filename,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			EndLine:  pos.Line,
+			Message:  fmt.Sprintf("string %s has %d occurrences, make it a constant", lits[0].Value, len(lits)),
+			Severity: models.SeverityWarning,
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,83 @@
+package linter
+
+import (
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"go/ast"
+	"go/token"
+)
+
+const (
+	gocycloRuleID = "gocyclo"
+
+	defaultCyclomaticThreshold = 10
+)
+
+// gocycloRule reports functions whose cyclomatic complexity is above the threshold
+type gocycloRule struct {
+	threshold int
+}
+
+func newGocycloRule(ls *LinterService) Rule {
+	threshold := ls.config.CyclomaticThreshold
+	if threshold <= 0 {
+		threshold = defaultCyclomaticThreshold
+	}
+	return &gocycloRule{threshold: threshold}
+}
+
+func (r *gocycloRule) ID() string {
+	return gocycloRuleID
+}
+
+func (r *gocycloRule) Description() string {
+	return "Functions should not have a cyclomatic complexity above the threshold"
+}
+
+func (r *gocycloRule) Check(fset *token.FileSet, f *ast.File) ([]*models.LintResult, error) {
+	var results []*models.LintResult
+	for _, decl := range f.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+
+		score := cyclomaticComplexity(funcDecl)
+		if score > r.threshold {
+			results = append(results, &models.LintResult{
+				File:     fset.Position(funcDecl.Pos()).Filename,
+				Line:     fset.Position(funcDecl.Pos()).Line,
+				EndLine:  fset.Position(funcDecl.End()).Line,
+				Function: funcDecl.Name.Name,
+				Message:  fmt.Sprintf("function has a cyclomatic complexity of %d which is higher than the threshold of %d", score, r.threshold),
+				Severity: models.SeverityWarning,
+			})
+		}
+	}
+	return results, nil
+}
+
+// cyclomaticComplexity counts 1 plus every decision point of the function
+func cyclomaticComplexity(node ast.Node) int {
+	complexity := 1
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if n.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
@@ -0,0 +1,159 @@
+package linter
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+const (
+	lllRuleID = "lll"
+
+	// LineLengthRunes measures a line by its number of characters
+	LineLengthRunes = "runes"
+	// LineLengthColumns measures a line by its display width, expanding tabs
+	LineLengthColumns = "columns"
+
+	defaultTabWidth = 4
+)
+
+// lllRule reports lines that are longer than MaxLineLength. It is a no-op when no limit is configured.
+type lllRule struct {
+	ls       *LinterService
+	limit    int
+	columns  bool
+	tabWidth int
+
+	ignoreStrings     bool
+	ignoreCommentURLs bool
+	ignoreGoGenerate  bool
+}
+
+func newLllRule(ls *LinterService) Rule {
+	tabWidth := ls.config.TabWidth
+	if tabWidth <= 0 {
+		tabWidth = defaultTabWidth
+	}
+	return &lllRule{
+		ls:                ls,
+		limit:             ls.config.MaxLineLength,
+		columns:           strings.EqualFold(ls.config.LineLengthMode, LineLengthColumns),
+		tabWidth:          tabWidth,
+		ignoreStrings:     ls.config.LineLengthIgnoreStrings,
+		ignoreCommentURLs: ls.config.LineLengthIgnoreURLs,
+		ignoreGoGenerate:  ls.config.LineLengthIgnoreGoGenerate,
+	}
+}
+
+func (r *lllRule) ID() string {
+	return lllRuleID
+}
+
+func (r *lllRule) Description() string {
+	return "Lines should not be longer than the maximum line length"
+}
+
+func (r *lllRule) Check(fset *token.FileSet, f *ast.File) ([]*models.LintResult, error) {
+	if r.limit <= 0 {
+		return nil, nil
+	}
+
+	tokFile := fset.File(f.Pos())
+	src, err := r.ls.source(tokFile)
+	if err != nil {
+		return nil, err
+	}
+
+	ignored := r.ignoredSpans(tokFile, f)
+	unit := "characters"
+	if r.columns {
+		unit = "columns"
+	}
+
+	var results []*models.LintResult
+	lineStart := 0
+	for lineNo, line := range bytes.Split(src, []byte("\n")) {
+		start := lineStart
+		lineStart += len(line) + 1
+
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if r.ignoreGoGenerate && bytes.HasPrefix(bytes.TrimSpace(line), []byte("//go:generate")) {
+			continue
+		}
+
+		length, overflow := r.measure(line)
+		if length <= r.limit || ignored.contains(start+overflow) {
+			continue
+		}
+
+		results = append(results, &models.LintResult{
+			File:     tokFile.Name(),
+			Line:     lineNo + 1,
+			Column:   overflow + 1,
+			EndLine:  lineNo + 1,
+			Message:  fmt.Sprintf("line is %d %s long, which exceeds the limit of %d", length, unit, r.limit),
+			Severity: models.SeverityWarning,
+		})
+	}
+	return results, nil
+}
+
+// measure returns the length of the line and the byte offset of the first
+// character beyond the limit
+func (r *lllRule) measure(line []byte) (int, int) {
+	length := 0
+	overflow := len(line)
+	for offset, char := range string(line) {
+		if r.columns && char == '\t' {
+			length += r.tabWidth - length%r.tabWidth
+		} else {
+			length++
+		}
+		if length > r.limit && overflow == len(line) {
+			overflow = offset
+		}
+	}
+	return length, overflow
+}
+
+// span is a range of byte offsets in a file
+type span struct {
+	start, end int
+}
+
+type spans []span
+
+func (s spans) contains(offset int) bool {
+	for _, sp := range s {
+		if offset >= sp.start && offset < sp.end {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoredSpans returns the string literals and URL comments that may exceed the limit
+func (r *lllRule) ignoredSpans(tokFile *token.File, f *ast.File) spans {
+	var ignored spans
+	if r.ignoreStrings {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				ignored = append(ignored, span{tokFile.Offset(lit.Pos()), tokFile.Offset(lit.End())})
+			}
+			return true
+		})
+	}
+	if r.ignoreCommentURLs {
+		for _, group := range f.Comments {
+			for _, comment := range group.List {
+				if strings.Contains(comment.Text, "://") {
+					ignored = append(ignored, span{tokFile.Offset(comment.Pos()), tokFile.Offset(comment.End())})
+				}
+			}
+		}
+	}
+	return ignored
+}
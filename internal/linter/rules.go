@@ -0,0 +1,128 @@
+package linter
+
+import (
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"go/ast"
+	"go/token"
+	"log"
+	"sort"
+	"strings"
+)
+
+// Rule is a single check that is run against every parsed Go file
+type Rule interface {
+	// ID is the name used to enable or disable the rule, e.g. "complexity"
+	ID() string
+	// Description is a one sentence summary of what the rule reports
+	Description() string
+	Check(fset *token.FileSet, f *ast.File) ([]*models.LintResult, error)
+}
+
+// RuleFactory creates a rule bound to the configuration of the given linter
+type RuleFactory func(ls *LinterService) Rule
+
+type registeredRule struct {
+	factory          RuleFactory
+	enabledByDefault bool
+}
+
+var registry = map[string]registeredRule{}
+
+// RegisterRule makes a rule available under id. A rule that is enabled by
+// default runs unless the config contains "disable-<id>", any other rule only
+// runs if the config contains "enable-<id>".
+func RegisterRule(id string, enabledByDefault bool, factory RuleFactory) {
+	if _, ok := registry[id]; ok {
+		panic(fmt.Sprintf("rule %q is already registered", id))
+	}
+	registry[id] = registeredRule{factory: factory, enabledByDefault: enabledByDefault}
+}
+
+// RuleIDs returns the IDs of all registered rules in alphabetical order
+func RuleIDs() []string {
+	ids := make([]string, 0, len(registry))
+	for id := range registry {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// resolveRules returns the IDs of the rules that run for the given
+// enable-X / disable-X entries, in alphabetical order. Later entries win over
+// earlier ones; unknown rules are logged and skipped.
+func resolveRules(entries []string) ([]string, error) {
+	enabled := make(map[string]bool)
+	for id, rule := range registry {
+		enabled[id] = rule.enabledByDefault
+	}
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		var id string
+		var enable bool
+		switch {
+		case strings.HasPrefix(entry, "enable-"):
+			id, enable = strings.TrimPrefix(entry, "enable-"), true
+		case strings.HasPrefix(entry, "disable-"):
+			id, enable = strings.TrimPrefix(entry, "disable-"), false
+		default:
+			return nil, fmt.Errorf("invalid rule entry %q: expected enable-<rule> or disable-<rule>", entry)
+		}
+
+		if _, ok := registry[id]; !ok {
+			log.Printf("Skipping unknown rule %q (available rules: %s)", id, strings.Join(RuleIDs(), ", "))
+			continue
+		}
+		enabled[id] = enable
+	}
+
+	var ids []string
+	for _, id := range RuleIDs() {
+		if enabled[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// activeRules returns the rules selected by the config, creating them on first
+// use. It is safe for concurrent use, e.g. by the requests of a controller.
+func (ls *LinterService) activeRules() ([]Rule, error) {
+	ls.rulesOnce.Do(func() {
+		ids, err := resolveRules(ls.config.Rules)
+		if err != nil {
+			ls.rulesErr = err
+			return
+		}
+
+		rules := make([]Rule, 0, len(ids))
+		for _, id := range ids {
+			rules = append(rules, registry[id].factory(ls))
+		}
+		ls.rules = rules
+	})
+	return ls.rules, ls.rulesErr
+}
+
+// ruleInfos describes the given rules for the report
+func ruleInfos(rules []Rule) []models.RuleInfo {
+	infos := make([]models.RuleInfo, 0, len(rules))
+	for _, rule := range rules {
+		infos = append(infos, models.RuleInfo{ID: rule.ID(), Description: rule.Description()})
+	}
+	return infos
+}
+
+func init() {
+	RegisterRule(complexityRuleID, true, newComplexityRule)
+	RegisterRule(directiveRuleID, true, newDirectiveRule)
+	RegisterRule(gocycloRuleID, false, newGocycloRule)
+	RegisterRule(goconstRuleID, false, newGoconstRule)
+	RegisterRule(lllRuleID, true, newLllRule)
+}
@@ -0,0 +1,217 @@
+package linter
+
+import (
+	"fmt"
+	"github.com/MikeMwita/go-strict/models"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_resolveRules(t *testing.T) {
+	type args struct {
+		entries []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "Test defaults",
+			args:    args{entries: nil},
+			want:    []string{"complexity", "directive", "lll"},
+			wantErr: false,
+		},
+		{
+			name:    "Test enable and disable",
+			args:    args{entries: []string{"enable-gocyclo", " enable-goconst", "disable-complexity"}},
+			want:    []string{"directive", "goconst", "gocyclo", "lll"},
+			wantErr: false,
+		},
+		{
+			name:    "Test later entries win",
+			args:    args{entries: []string{"enable-gocyclo", "disable-gocyclo"}},
+			want:    []string{"complexity", "directive", "lll"},
+			wantErr: false,
+		},
+		{
+			name:    "Test unknown rules are skipped",
+			args:    args{entries: []string{"enable-unused", "disable-errcheck"}},
+			want:    []string{"complexity", "directive", "lll"},
+			wantErr: false,
+		},
+		{
+			name:    "Test malformed entry",
+			args:    args{entries: []string{"gocyclo"}},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveRules(tt.args.entries)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolveRules() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveRules() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRules_Check(t *testing.T) {
+	const code = `package main
+
+import "fmt"
+
+type T struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+func Route(method string, a, b bool) {
+	switch method {
+	case "GET":
+		fmt.Println("GET")
+	case "POST":
+		if a && b {
+			fmt.Println("GET")
+		}
+	default:
+	}
+	const name = "GET"
+}
+`
+	tests := []struct {
+		name   string
+		config *models.LintConfig
+		want   []string
+	}{
+		{
+			name:   "Test gocyclo",
+			config: &models.LintConfig{Rules: []string{"disable-complexity", "enable-gocyclo"}, CyclomaticThreshold: 3},
+			want:   []string{"gocyclo: function has a cyclomatic complexity of 5 which is higher than the threshold of 3"},
+		},
+		{
+			name:   "Test goconst",
+			config: &models.LintConfig{Rules: []string{"disable-complexity", "enable-goconst"}},
+			want:   []string{`goconst: string "GET" has 3 occurrences, make it a constant`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := parser.ParseFile(testFset, "rules.go", code, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ls := NewLinterService(tt.config, nil)
+			results, err := ls.lintFile(testFset, f)
+			if err != nil {
+				t.Errorf("lintFile() error = %v", err)
+				return
+			}
+			var got []string
+			for _, result := range results {
+				got = append(got, result.Rule+": "+result.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lintFile() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLllRule_Check(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *models.LintConfig
+		want   []string
+	}{
+		{
+			name:   "Test no limit",
+			config: &models.LintConfig{},
+			want:   nil,
+		},
+		{
+			name:   "Test runes",
+			config: &models.LintConfig{MaxLineLength: 40},
+			want: []string{
+				"4:41: line is 56 characters long, which exceeds the limit of 40",
+				"6:41: line is 52 characters long, which exceeds the limit of 40",
+				"9:41: line is 55 characters long, which exceeds the limit of 40",
+				"10:41: line is 43 characters long, which exceeds the limit of 40",
+				"11:41: line is 44 characters long, which exceeds the limit of 40",
+			},
+		},
+		{
+			name:   "Test columns expand tabs",
+			config: &models.LintConfig{MaxLineLength: 40, LineLengthMode: "columns", TabWidth: 8},
+			want: []string{
+				"4:41: line is 56 columns long, which exceeds the limit of 40",
+				"6:41: line is 52 columns long, which exceeds the limit of 40",
+				"9:34: line is 62 columns long, which exceeds the limit of 40",
+				"10:34: line is 50 columns long, which exceeds the limit of 40",
+				"11:34: line is 51 columns long, which exceeds the limit of 40",
+			},
+		},
+		{
+			name: "Test ignored lines",
+			config: &models.LintConfig{
+				MaxLineLength:              40,
+				LineLengthIgnoreStrings:    true,
+				LineLengthIgnoreURLs:       true,
+				LineLengthIgnoreGoGenerate: true,
+			},
+			want: []string{
+				"10:41: line is 43 characters long, which exceeds the limit of 40",
+				"11:41: line is 44 characters long, which exceeds the limit of 40",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Rules = []string{"disable-complexity"}
+			ls := NewLinterService(tt.config, nil)
+			results, err := ls.lintFile(testFset, parseFile("./testdata/long_lines.go"))
+			if err != nil {
+				t.Errorf("lintFile() error = %v", err)
+				return
+			}
+			var got []string
+			for _, result := range results {
+				got = append(got, fmt.Sprintf("%d:%d: %s", result.Line, result.Column, result.Message))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lintFile() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLllRule_Check_parsedSource(t *testing.T) {
+	// the file does not exist on disk, so the rule has to use the parsed source
+	src := []byte("package main\n\nvar s = \"a line that is longer than the limit\"\n")
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filepath.Join(t.TempDir(), "missing.go"), src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := NewLinterService(&models.LintConfig{MaxLineLength: 20, Rules: []string{"disable-complexity"}}, nil)
+	tokFile := fset.File(f.Pos())
+	ls.sources.Store(tokFile, src)
+	defer ls.sources.Delete(tokFile)
+
+	results, err := ls.lintFile(fset, f)
+	if err != nil {
+		t.Fatalf("lintFile() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Line != 3 {
+		t.Errorf("lintFile() got = %v, want a long line at line 3", results)
+	}
+}
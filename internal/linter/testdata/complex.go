@@ -0,0 +1,12 @@
+package testdata
+
+func Classify(values []int) string {
+	for _, v := range values {
+		if v < 0 {
+			return "negative"
+		} else if v == 0 {
+			return "zero"
+		}
+	}
+	return "positive"
+}
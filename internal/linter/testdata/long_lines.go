@@ -0,0 +1,12 @@
+package testdata
+
+import "fmt"
+//go:generate stringer -type=Pill -output=pill_string.go
+
+// see https://github.com/MikeMwita/go-strict/issues
+
+func Print() {
+	fmt.Println("a very long string literal is fine here")
+	var someLongVariableName, other = 1, 23456
+	_, _ = someLongVariableName, other // short
+}
@@ -0,0 +1,9 @@
+package testdata
+
+func Add(x, y int) int {
+	return x + y
+}
+
+func Subtract(x, y int) int {
+	return x - y
+}
@@ -1,17 +1,90 @@
 package models
 
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
 type LintResult struct {
 	File     string `json:"file,omitempty"`
 	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	EndLine  int    `json:"end_line,omitempty"`
 	Message  string `json:"message,omitempty"`
 	Severity string `json:"severity,omitempty"`
 	Function string `json:"function,omitempty"`
+	Rule     string `json:"rule,omitempty"`
+	// Complexity is the cognitive complexity breakdown of complexity findings
+	Complexity *ComplexityReport `json:"complexity,omitempty"`
+}
+
+// RuleInfo describes a rule that was run
+type RuleInfo struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+}
+
+// ComplexityReport is the cognitive complexity breakdown of a function
+type ComplexityReport struct {
+	Score int `json:"score"`
+	// Threshold is the limit the score exceeded: the threshold for warnings, the maximum for errors
+	Threshold  int                   `json:"threshold"`
+	Increments []ComplexityIncrement `json:"increments"`
+}
+
+// ComplexityIncrement is a single contribution to a complexity score
+type ComplexityIncrement struct {
+	Kind      string `json:"kind"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	Increment int    `json:"increment"`
+	Nesting   int    `json:"nesting"`
+	// Total is the running score including this increment
+	Total int `json:"total"`
 }
 
 type LintConfig struct {
-	Rules         []string `toml:"rules"`
-	Output        string   `toml:"output"`
-	Threshold     int      `toml:"threshold"`
-	MaxComplexity int      `toml:"max_complexity"`
-	MaxLineLength int      `toml:"max_line_length"`
+	Rules []string `toml:"rules"`
+	// Output is a comma separated list of output formats, each optionally followed by =path
+	Output string `toml:"output"`
+	// OutputFile receives the formats without a path of their own; empty means stdout
+	OutputFile string `toml:"output_file"`
+	// Jobs is the number of files analysed in parallel; 0 means GOMAXPROCS
+	Jobs int `toml:"jobs"`
+	// CacheDir holds the cached analyses; empty means the user cache directory
+	CacheDir string `toml:"cache_dir"`
+	// NoCache analyses every file again instead of using the cache
+	NoCache bool `toml:"no_cache"`
+	// Paths are the files and directories that are linted when none are given on the command line
+	Paths []string `toml:"paths"`
+	// Include are doublestar globs, relative to each path, of the files to lint; empty means all
+	Include []string `toml:"include"`
+	// Exclude are doublestar globs, relative to each path, of the files and directories to skip
+	Exclude []string `toml:"exclude"`
+	// SkipTests skips _test.go files
+	SkipTests bool `toml:"skip_tests"`
+	// SkipVendor skips vendor directories
+	SkipVendor bool `toml:"skip_vendor"`
+	// MaxDepth is the number of directory levels walked below each path; 1 means
+	// only the path itself and 0 is unrestricted
+	MaxDepth int `toml:"max_depth"`
+	// Threshold is the cognitive complexity above which a function is reported as a warning
+	Threshold int `toml:"threshold"`
+	// MaxComplexity is the cognitive complexity above which a function is reported as an error; 0 disables errors
+	MaxComplexity int `toml:"max_complexity"`
+	MaxLineLength int `toml:"max_line_length"`
+	// ClosureMinLines reports function literals spanning at least this many lines
+	// as functions of their own (e.g. main.func1); 0 scores them as part of their parent
+	ClosureMinLines int `toml:"closure_min_lines"`
+	// CyclomaticThreshold is used by the gocyclo rule, defaults to 10
+	CyclomaticThreshold int `toml:"cyclomatic_threshold"`
+	// ConstMinOccurrences is used by the goconst rule, defaults to 3
+	ConstMinOccurrences int `toml:"const_min_occurrences"`
+	// LineLengthMode is "runes" (default) or "columns", which expands tabs to TabWidth
+	LineLengthMode             string `toml:"line_length_mode"`
+	TabWidth                   int    `toml:"tab_width"`
+	LineLengthIgnoreStrings    bool   `toml:"line_length_ignore_strings"`
+	LineLengthIgnoreURLs       bool   `toml:"line_length_ignore_urls"`
+	LineLengthIgnoreGoGenerate bool   `toml:"line_length_ignore_go_generate"`
 }
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Report is the outcome of a lint run: every file and function that was
+// analysed, and the findings that exceeded a limit
+type Report struct {
+	Rules   []RuleInfo    `json:"rules"`
+	Files   []*FileReport `json:"files"`
+	Results []*LintResult `json:"results"`
+	// Suppressed is the number of results removed by directives or a baseline
+	Suppressed int `json:"suppressed"`
+}
+
+// FileReport lists the functions of an analysed file
+type FileReport struct {
+	Path      string            `json:"path"`
+	Package   string            `json:"package"`
+	Functions []*FunctionReport `json:"functions"`
+	// Suppressed is the number of results removed by directives in the file
+	Suppressed int `json:"suppressed,omitempty"`
+}
+
+// FunctionReport is the cognitive complexity of a single function or split off closure
+type FunctionReport struct {
+	Name       string `json:"name"`
+	Receiver   string `json:"receiver,omitempty"`
+	Line       int    `json:"line"`
+	EndLine    int    `json:"end_line"`
+	Complexity int    `json:"complexity"`
+}
+
+// Summary holds the statistics of a report over all analysed functions
+type Summary struct {
+	Files     int `json:"files"`
+	Functions int `json:"functions"`
+	// Findings is the number of functions above the complexity threshold;
+	// Suppressed is the number of results removed by directives or a baseline
+	Findings          int               `json:"findings"`
+	Suppressed        int               `json:"suppressed"`
+	TotalComplexity   int               `json:"total_complexity"`
+	HighestComplexity int               `json:"highest_complexity"`
+	Average           float64           `json:"average"`
+	Median            float64           `json:"median"`
+	P90               int               `json:"p90"`
+	P99               int               `json:"p99"`
+	Histogram         []HistogramBucket `json:"histogram"`
+}
+
+// HistogramBucket counts the functions whose complexity is within [Min, Max];
+// a Max of -1 means the bucket is unbounded
+type HistogramBucket struct {
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+	Count int `json:"count"`
+}
+
+// Label returns the range of the bucket, e.g. "6-10" or "51+"
+func (b HistogramBucket) Label() string {
+	switch {
+	case b.Max < 0:
+		return fmt.Sprintf("%d+", b.Min)
+	case b.Min == b.Max:
+		return fmt.Sprintf("%d", b.Min)
+	default:
+		return fmt.Sprintf("%d-%d", b.Min, b.Max)
+	}
+}
+
+// histogramBuckets are the complexity ranges of the summary histogram
+var histogramBuckets = []HistogramBucket{
+	{Min: 0, Max: 0},
+	{Min: 1, Max: 5},
+	{Min: 6, Max: 10},
+	{Min: 11, Max: 20},
+	{Min: 21, Max: 50},
+	{Min: 51, Max: -1},
+}
+
+// Summary computes the statistics of the report
+func (r *Report) Summary() *Summary {
+	summary := &Summary{
+		Files:      len(r.Files),
+		Suppressed: r.Suppressed,
+		Histogram:  append([]HistogramBucket(nil), histogramBuckets...),
+	}
+
+	var scores []int
+	for _, file := range r.Files {
+		for _, function := range file.Functions {
+			scores = append(scores, function.Complexity)
+		}
+	}
+	for _, result := range r.Results {
+		if result.Complexity != nil {
+			summary.Findings++
+		}
+	}
+
+	summary.Functions = len(scores)
+	if len(scores) == 0 {
+		return summary
+	}
+
+	sort.Ints(scores)
+	for _, score := range scores {
+		summary.TotalComplexity += score
+		for i := range summary.Histogram {
+			bucket := &summary.Histogram[i]
+			if score >= bucket.Min && (bucket.Max < 0 || score <= bucket.Max) {
+				bucket.Count++
+				break
+			}
+		}
+	}
+
+	n := len(scores)
+	summary.HighestComplexity = scores[n-1]
+	summary.Average = float64(summary.TotalComplexity) / float64(n)
+	if n%2 == 1 {
+		summary.Median = float64(scores[n/2])
+	} else {
+		summary.Median = float64(scores[n/2-1]+scores[n/2]) / 2
+	}
+	summary.P90 = percentile(scores, 90)
+	summary.P99 = percentile(scores, 99)
+	return summary
+}
+
+// percentile returns the nearest-rank percentile of the sorted scores
+func percentile(sorted []int, p float64) int {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReport_Summary(t *testing.T) {
+	tests := []struct {
+		name   string
+		report *Report
+		want   *Summary
+	}{
+		{
+			name:   "Test empty report",
+			report: &Report{},
+			want: &Summary{
+				Histogram: histogramBuckets,
+			},
+		},
+		{
+			name: "Test all functions are counted",
+			report: &Report{
+				Files: []*FileReport{
+					{Path: "a.go", Functions: []*FunctionReport{{Complexity: 0}, {Complexity: 3}, {Complexity: 12}}},
+					{Path: "b.go", Functions: []*FunctionReport{{Complexity: 7}}},
+					{Path: "c.go"},
+				},
+				Results: []*LintResult{
+					{Complexity: &ComplexityReport{Score: 12, Threshold: 10}},
+					{Message: "line is too long"},
+				},
+			},
+			want: &Summary{
+				Files:             3,
+				Functions:         4,
+				Findings:          1,
+				TotalComplexity:   22,
+				HighestComplexity: 12,
+				Average:           5.5,
+				Median:            5,
+				P90:               12,
+				P99:               12,
+				Histogram: []HistogramBucket{
+					{Min: 0, Max: 0, Count: 1},
+					{Min: 1, Max: 5, Count: 1},
+					{Min: 6, Max: 10, Count: 1},
+					{Min: 11, Max: 20, Count: 1},
+					{Min: 21, Max: 50, Count: 0},
+					{Min: 51, Max: -1, Count: 0},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.Summary(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Summary() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_percentile(t *testing.T) {
+	sorted := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	tests := []struct {
+		name string
+		p    float64
+		want int
+	}{
+		{name: "Test median", p: 50, want: 5},
+		{name: "Test p90", p: 90, want: 9},
+		{name: "Test p99", p: 99, want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(sorted, tt.p); got != tt.want {
+				t.Errorf("percentile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,43 +1,374 @@
 package complexity
 
 import (
+	"errors"
 	"fmt"
 	"github.com/MikeMwita/go-strict/models"
 	"go/ast"
 	"go/token"
+	"strings"
 )
 
-type ComplexityService struct {
-	nesting int
+// Increment is a single contribution to the cognitive complexity of a function
+type Increment struct {
+	Kind     string
+	Position token.Position
+	Value    int
+	Nesting  int
 }
 
+// Function is the complexity breakdown of a single function body: either a
+// declared function or a closure that is scored on its own
+type Function struct {
+	Name       string
+	Node       ast.Node
+	Increments []Increment
+}
+
+// Score returns the cognitive complexity of the function
+func (f *Function) Score() int {
+	return Total(f.Increments)
+}
+
+type ComplexityService struct{}
+
+// Calculate returns the cognitive complexity of the given node
 func (cs *ComplexityService) Calculate(fset *token.FileSet, node ast.Node) (int, error) {
-	complexity := 1
-	ast.Inspect(node, func(n ast.Node) bool {
-		switch n.(type) {
-		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
-			complexity += cs.Complexity(n)
-		case *ast.CaseClause:
-			complexity += cs.Complexity(n)
-		}
-		return true
-	})
+	increments, err := cs.Increments(fset, node)
+	if err != nil {
+		return 0, err
+	}
+	return Total(increments), nil
+}
+
+// Increments walks the given node and returns every increment that contributes
+// to its cognitive complexity, in source order.
+//
+// The scoring follows the SonarSource cognitive complexity model:
+//   - if, switch, select, for and range add 1 plus the current nesting level
+//   - else if and else add 1 without a nesting penalty
+//   - the bodies of those statements and of function literals are nested one level deeper
+//   - every change of operator in a sequence of && and || adds 1
+//   - goto, labeled break, labeled continue and every recursive call add 1
+//
+// Recursion is only detected when node is the *ast.FuncDecl being scored.
+func (cs *ComplexityService) Increments(fset *token.FileSet, node ast.Node) ([]Increment, error) {
+	if node == nil {
+		return nil, errors.New("cannot calculate complexity of a nil node")
+	}
 
-	return complexity, nil
+	v := newVisitor(fset, "", nil, nil)
+	ast.Walk(v, node)
+	return v.increments, nil
 }
 
-func (cs *ComplexityService) Complexity(stmt ast.Node) int {
-	return 1 + cs.nesting
+// Functions scores funcDecl and returns it as the first entry. Function literals
+// for which split returns true do not contribute to their parent; they are scored
+// as separate entries instead, starting again at nesting level 0. Closures are
+// named after the convention of the Go toolchain: main.func1, main.func1.1, ...
+func (cs *ComplexityService) Functions(fset *token.FileSet, funcDecl *ast.FuncDecl, split func(*ast.FuncLit) bool) ([]*Function, error) {
+	if funcDecl == nil {
+		return nil, errors.New("cannot calculate complexity of a nil function declaration")
+	}
+
+	counters := make(map[string]int)
+	root := newVisitor(fset, funcDecl.Name.Name, split, counters)
+	ast.Walk(root, funcDecl)
+
+	functions := []*Function{{Name: funcDecl.Name.Name, Node: funcDecl, Increments: root.increments}}
+	pending := root.closures
+	for len(pending) > 0 {
+		closure := pending[0]
+		pending = pending[1:]
+
+		v := newVisitor(fset, closure.Name, split, counters)
+		// a call of the enclosing function is recursion whether or not the closure is split off
+		v.funcDecl = funcDecl
+		v.walk(closure.Node.(*ast.FuncLit).Body)
+		closure.Increments = v.increments
+
+		functions = append(functions, closure)
+		pending = append(v.closures, pending...)
+	}
+
+	return functions, nil
 }
 
+// Total sums the given increments
+func Total(increments []Increment) int {
+	total := 0
+	for _, inc := range increments {
+		total += inc.Value
+	}
+	return total
+}
+
+// GetDetail renders the complexity breakdown of a result, one increment per
+// line, indented by its nesting level
 func GetDetail(result *models.LintResult) string {
-	var details string
-	details += fmt.Sprintf(" (Complexity details:\n%s)", result.Message)
-	return details
+	if result.Complexity == nil {
+		return ""
+	}
+
+	var details strings.Builder
+	for _, inc := range result.Complexity.Increments {
+		indent := strings.Repeat("  ", inc.Nesting)
+		fmt.Fprintf(&details, "%s+ %d (found '%s' at line: %d, complexity = %d)\n", indent, inc.Increment, inc.Kind, inc.Line, inc.Total)
+	}
+	return details.String()
 }
 
 func NewComplexityService() *ComplexityService {
-	return &ComplexityService{
-		nesting: 0,
+	return &ComplexityService{}
+}
+
+// visitor keeps the nesting state of a single function body
+type visitor struct {
+	fset       *token.FileSet
+	nesting    int
+	increments []Increment
+	counted    map[*ast.BinaryExpr]bool
+	funcDecl   *ast.FuncDecl
+
+	// scope is the name of the function being walked, used to name its closures
+	scope    string
+	split    func(*ast.FuncLit) bool
+	counters map[string]int
+	closures []*Function
+}
+
+func newVisitor(fset *token.FileSet, scope string, split func(*ast.FuncLit) bool, counters map[string]int) *visitor {
+	if counters == nil {
+		counters = make(map[string]int)
+	}
+	return &visitor{
+		fset:     fset,
+		counted:  make(map[*ast.BinaryExpr]bool),
+		scope:    scope,
+		split:    split,
+		counters: counters,
+	}
+}
+
+// logicalOp is a && or || operator of a boolean sequence
+type logicalOp struct {
+	op  token.Token
+	pos token.Pos
+}
+
+func (v *visitor) Visit(n ast.Node) ast.Visitor {
+	switch node := n.(type) {
+	case *ast.FuncDecl:
+		v.funcDecl = node
+	case *ast.IfStmt:
+		v.structural("if", node.Pos())
+		v.walkIf(node)
+		return nil
+	case *ast.SwitchStmt:
+		v.structural("switch", node.Pos())
+		v.walk(node.Init)
+		v.walk(node.Tag)
+		v.nested(node.Body)
+		return nil
+	case *ast.TypeSwitchStmt:
+		v.structural("switch", node.Pos())
+		v.walk(node.Init)
+		v.walk(node.Assign)
+		v.nested(node.Body)
+		return nil
+	case *ast.SelectStmt:
+		v.structural("select", node.Pos())
+		v.nested(node.Body)
+		return nil
+	case *ast.ForStmt:
+		v.structural("for", node.Pos())
+		v.walk(node.Init)
+		v.walk(node.Cond)
+		v.walk(node.Post)
+		v.nested(node.Body)
+		return nil
+	case *ast.RangeStmt:
+		v.structural("range", node.Pos())
+		v.walk(node.Key)
+		v.walk(node.Value)
+		v.walk(node.X)
+		v.nested(node.Body)
+		return nil
+	case *ast.FuncLit:
+		v.visitFuncLit(node)
+		return nil
+	case *ast.BinaryExpr:
+		v.visitBinary(node)
+	case *ast.BranchStmt:
+		v.visitBranch(node)
+	case *ast.CallExpr:
+		if v.isRecursive(node) {
+			v.hybrid("recursion", node.Pos())
+		}
+	}
+	return v
+}
+
+// visitFuncLit either nests the closure into the current function or, if it is
+// split off, queues it to be scored on its own
+func (v *visitor) visitFuncLit(lit *ast.FuncLit) {
+	name := v.closureName()
+	if v.split != nil && v.split(lit) {
+		v.closures = append(v.closures, &Function{Name: name, Node: lit})
+		return
 	}
+
+	scope := v.scope
+	v.scope = name
+	v.nested(lit.Body)
+	v.scope = scope
+}
+
+// closureName returns the name of the next closure in the current scope
+func (v *visitor) closureName() string {
+	v.counters[v.scope]++
+	n := v.counters[v.scope]
+	if strings.Contains(v.scope, ".") {
+		return fmt.Sprintf("%s.%d", v.scope, n)
+	}
+	return fmt.Sprintf("%s.func%d", v.scope, n)
+}
+
+// visitBranch scores jumps to a label; plain break and continue are free
+func (v *visitor) visitBranch(stmt *ast.BranchStmt) {
+	switch {
+	case stmt.Tok == token.GOTO:
+		v.hybrid("goto", stmt.Pos())
+	case stmt.Label != nil && stmt.Tok == token.BREAK:
+		v.hybrid("labeled break", stmt.Pos())
+	case stmt.Label != nil && stmt.Tok == token.CONTINUE:
+		v.hybrid("labeled continue", stmt.Pos())
+	}
+}
+
+// isRecursive reports whether call invokes the function declaration being scored,
+// either directly by name or, for methods, through the receiver
+func (v *visitor) isRecursive(call *ast.CallExpr) bool {
+	if v.funcDecl == nil {
+		return false
+	}
+
+	fun := call.Fun
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+
+	name := v.funcDecl.Name.Name
+	if v.funcDecl.Recv == nil {
+		ident, ok := fun.(*ast.Ident)
+		return ok && ident.Name == name
+	}
+
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	recv, ok := sel.X.(*ast.Ident)
+	return ok && recv.Name == receiverName(v.funcDecl)
+}
+
+// receiverName returns the name of the method receiver, or "" if it is unnamed
+func receiverName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 || len(funcDecl.Recv.List[0].Names) == 0 {
+		return ""
+	}
+	name := funcDecl.Recv.List[0].Names[0].Name
+	if name == "_" {
+		return ""
+	}
+	return name
+}
+
+// visitBinary adds one increment for every change of operator in the sequence of
+// && and || operators that starts at expr. Parenthesised operands belong to the
+// same sequence, so each sequence is only counted from its outermost expression.
+func (v *visitor) visitBinary(expr *ast.BinaryExpr) {
+	if !isLogical(expr.Op) || v.counted[expr] {
+		return
+	}
+
+	var last token.Token
+	for _, op := range v.collectLogicalOps(expr) {
+		if op.op != last {
+			v.hybrid(op.op.String(), op.pos)
+			last = op.op
+		}
+	}
+}
+
+// collectLogicalOps flattens a sequence of && and || operators in source order
+func (v *visitor) collectLogicalOps(expr ast.Expr) []logicalOp {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return v.collectLogicalOps(e.X)
+	case *ast.BinaryExpr:
+		if !isLogical(e.Op) {
+			return nil
+		}
+		v.counted[e] = true
+		ops := v.collectLogicalOps(e.X)
+		ops = append(ops, logicalOp{op: e.Op, pos: e.OpPos})
+		return append(ops, v.collectLogicalOps(e.Y)...)
+	}
+	return nil
+}
+
+func isLogical(op token.Token) bool {
+	return op == token.LAND || op == token.LOR
+}
+
+// walkIf walks an if statement together with its else if / else chain.
+// The increment for the if itself has already been recorded by the caller.
+func (v *visitor) walkIf(stmt *ast.IfStmt) {
+	v.walk(stmt.Init)
+	v.walk(stmt.Cond)
+	v.nested(stmt.Body)
+
+	switch elseStmt := stmt.Else.(type) {
+	case *ast.IfStmt:
+		v.hybrid("else if", elseStmt.Pos())
+		v.walkIf(elseStmt)
+	case *ast.BlockStmt:
+		v.hybrid("else", elseStmt.Pos())
+		v.nested(elseStmt)
+	}
+}
+
+func (v *visitor) walk(n ast.Node) {
+	if n != nil {
+		ast.Walk(v, n)
+	}
+}
+
+func (v *visitor) nested(n ast.Node) {
+	v.nesting++
+	v.walk(n)
+	v.nesting--
+}
+
+// structural records an increment that is penalised by the current nesting level
+func (v *visitor) structural(kind string, pos token.Pos) {
+	v.add(kind, pos, 1+v.nesting)
+}
+
+// hybrid records an increment that ignores the current nesting level
+func (v *visitor) hybrid(kind string, pos token.Pos) {
+	v.add(kind, pos, 1)
+}
+
+func (v *visitor) add(kind string, pos token.Pos, value int) {
+	v.increments = append(v.increments, Increment{
+		Kind:     kind,
+		Position: v.fset.Position(pos),
+		Value:    value,
+		Nesting:  v.nesting,
+	})
 }
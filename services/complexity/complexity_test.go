@@ -0,0 +1,456 @@
+package complexity
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+func TestComplexityService_Calculate(t *testing.T) {
+	type args struct {
+		code string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "Test function without branches",
+			args: args{
+				code: "func Add(x, y int) int { return x + y }",
+			},
+			want:    0,
+			wantErr: false,
+		},
+		{
+			name: "Test nested if inside for",
+			args: args{
+				code: `func Loop(n int) {
+	for i := 0; i < n; i++ {
+		if i > 2 {
+			println(i)
+		}
+	}
+}`,
+			},
+			want:    3,
+			wantErr: false,
+		},
+		{
+			name: "Test else if and else are not penalised for nesting",
+			args: args{
+				code: `func Sign(values []int) {
+	for _, v := range values {
+		if v < 0 {
+			println("negative")
+		} else if v == 0 {
+			println("zero")
+		} else {
+			println("positive")
+		}
+	}
+}`,
+			},
+			want:    5,
+			wantErr: false,
+		},
+		{
+			name: "Test switch counts once and nests its cases",
+			args: args{
+				code: `func Switch(x int) {
+	switch x {
+	case 1:
+		println(1)
+	case 2:
+		if x > 1 {
+			println(2)
+		}
+	default:
+		println(3)
+	}
+}`,
+			},
+			want:    3,
+			wantErr: false,
+		},
+		{
+			name: "Test deep nesting",
+			args: args{
+				code: `func Deep(m map[string][]int) {
+	for k, values := range m {
+		for _, v := range values {
+			if v > 0 {
+				println(k)
+			}
+		}
+	}
+}`,
+			},
+			want:    6,
+			wantErr: false,
+		},
+		{
+			name: "Test function literal increases nesting",
+			args: args{
+				code: `func Closure(ch chan int) {
+	go func() {
+		select {
+		case v := <-ch:
+			println(v)
+		}
+	}()
+}`,
+			},
+			want:    2,
+			wantErr: false,
+		},
+		{
+			name: "Test sequence of like boolean operators",
+			args: args{
+				code: `func All(a, b, c bool) bool {
+	return a && b && c
+}`,
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "Test mixed boolean operators",
+			args: args{
+				code: `func Mixed(a, b, c, d bool) {
+	if a && b || c && d {
+		println("mixed")
+	}
+}`,
+			},
+			want:    4,
+			wantErr: false,
+		},
+		{
+			name: "Test parenthesised operands belong to the same sequence",
+			args: args{
+				code: `func Grouped(a, b, c, d bool) bool {
+	return (a && b) && (c || d)
+}`,
+			},
+			want:    2,
+			wantErr: false,
+		},
+		{
+			name: "Test negation starts a new sequence",
+			args: args{
+				code: `func Negated(a, b, c bool) bool {
+	return a && !(b && c)
+}`,
+			},
+			want:    2,
+			wantErr: false,
+		},
+		{
+			name: "Test goto and labeled branches",
+			args: args{
+				code: `func Jump(rows [][]int) {
+outer:
+	for _, row := range rows {
+		for _, v := range row {
+			if v < 0 {
+				break outer
+			}
+			if v == 0 {
+				continue outer
+			}
+		}
+	}
+	goto done
+done:
+	for {
+		break
+	}
+}`,
+			},
+			want:    13,
+			wantErr: false,
+		},
+		{
+			name: "Test direct recursion",
+			args: args{
+				code: `func Fibonacci(n int) int {
+	if n <= 1 {
+		return n
+	}
+	return Fibonacci(n-1) + Fibonacci(n-2)
+}`,
+			},
+			want:    3,
+			wantErr: false,
+		},
+		{
+			name: "Test recursion through the method receiver",
+			args: args{
+				code: `func (t *Tree) Size() int {
+	if t == nil {
+		return 0
+	}
+	return 1 + t.Left.Size() + t.Size()
+}`,
+			},
+			want:    2,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := NewComplexityService()
+			fset := token.NewFileSet()
+			got, err := cs.Calculate(fset, parseFunction(fset, tt.args.code))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Calculate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Calculate() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComplexityService_Increments(t *testing.T) {
+	type args struct {
+		code string
+	}
+	type increment struct {
+		Kind    string
+		Line    int
+		Value   int
+		Nesting int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []increment
+		wantErr bool
+	}{
+		{
+			name: "Test nesting levels are reported",
+			args: args{
+				code: `func Nested(values []int) {
+	if len(values) > 0 {
+		for _, v := range values {
+			if v > 0 {
+				println(v)
+			} else {
+				println(-v)
+			}
+		}
+	}
+}`,
+			},
+			want: []increment{
+				{Kind: "if", Line: 3, Value: 1, Nesting: 0},
+				{Kind: "range", Line: 4, Value: 2, Nesting: 1},
+				{Kind: "if", Line: 5, Value: 3, Nesting: 2},
+				{Kind: "else", Line: 7, Value: 1, Nesting: 2},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test each operator change is reported",
+			args: args{
+				code: `func Mixed(a, b, c bool) {
+	if a || b &&
+		c {
+		println("mixed")
+	}
+}`,
+			},
+			want: []increment{
+				{Kind: "if", Line: 3, Value: 1, Nesting: 0},
+				{Kind: "||", Line: 3, Value: 1, Nesting: 0},
+				{Kind: "&&", Line: 3, Value: 1, Nesting: 0},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test jumps and recursion are labeled",
+			args: args{
+				code: `func Walk(n int) {
+loop:
+	for n > 0 {
+		n--
+		if n == 5 {
+			continue loop
+		}
+		Walk(n)
+	}
+	goto loop
+}`,
+			},
+			want: []increment{
+				{Kind: "for", Line: 4, Value: 1, Nesting: 0},
+				{Kind: "if", Line: 6, Value: 2, Nesting: 1},
+				{Kind: "labeled continue", Line: 7, Value: 1, Nesting: 2},
+				{Kind: "recursion", Line: 9, Value: 1, Nesting: 1},
+				{Kind: "goto", Line: 11, Value: 1, Nesting: 0},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := NewComplexityService()
+			fset := token.NewFileSet()
+			increments, err := cs.Increments(fset, parseFunction(fset, tt.args.code))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Increments() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			var got []increment
+			for _, inc := range increments {
+				got = append(got, increment{Kind: inc.Kind, Line: inc.Position.Line, Value: inc.Value, Nesting: inc.Nesting})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Increments() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// parseFunction parses a single function declaration and returns its *ast.FuncDecl
+func parseFunction(fset *token.FileSet, code string) *ast.FuncDecl {
+	f, err := parser.ParseFile(fset, "", "package main\n"+code, 0)
+	if err != nil {
+		panic(err)
+	}
+	for _, decl := range f.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+			return funcDecl
+		}
+	}
+	return nil
+}
+
+func TestComplexityService_Functions(t *testing.T) {
+	type args struct {
+		code     string
+		minLines int
+	}
+	type function struct {
+		Name  string
+		Score int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []function
+		wantErr bool
+	}{
+		{
+			name: "Test closures stay in their parent",
+			args: args{
+				code: `func main() {
+	go func() {
+		if true {
+			println()
+		}
+	}()
+}`,
+				minLines: 0,
+			},
+			want: []function{
+				{Name: "main", Score: 2},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test large closures are split off and named",
+			args: args{
+				code: `func main() {
+	go func() {
+		if true {
+			println()
+		}
+		defer func() {
+			for {
+				break
+			}
+		}()
+	}()
+	f := func() {
+		if false {
+		}
+	}
+	f()
+}`,
+				minLines: 3,
+			},
+			want: []function{
+				{Name: "main", Score: 0},
+				{Name: "main.func1", Score: 1},
+				{Name: "main.func1.1", Score: 1},
+				{Name: "main.func2", Score: 1},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test recursion in a split off closure",
+			args: args{
+				code: `func walk(n int) {
+	visit := func() {
+		println(n)
+		walk(n - 1)
+	}
+	visit()
+}`,
+				minLines: 3,
+			},
+			want: []function{
+				{Name: "walk", Score: 0},
+				{Name: "walk.func1", Score: 1},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test recursion in an inline closure",
+			args: args{
+				code: `func walk(n int) {
+	visit := func() {
+		println(n)
+		walk(n - 1)
+	}
+	visit()
+}`,
+				minLines: 0,
+			},
+			want: []function{
+				{Name: "walk", Score: 1},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := NewComplexityService()
+			fset := token.NewFileSet()
+			split := func(lit *ast.FuncLit) bool {
+				lines := fset.Position(lit.End()).Line - fset.Position(lit.Pos()).Line + 1
+				return tt.args.minLines > 0 && lines >= tt.args.minLines
+			}
+			functions, err := cs.Functions(fset, parseFunction(fset, tt.args.code), split)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Functions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			var got []function
+			for _, fn := range functions {
+				got = append(got, function{Name: fn.Name, Score: fn.Score()})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Functions() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/MikeMwita/go-strict/models"
 	"github.com/MikeMwita/go-strict/services/complexity"
+	"io"
 )
 
 func PrintSummary(fileCount, funcCount, maxComplexity, totalComplexity, funcCountSum, complexLineCount int) {
@@ -18,22 +19,28 @@ func PrintSummary(fileCount, funcCount, maxComplexity, totalComplexity, funcCoun
 	fmt.Printf("%d complex lines\n\n", complexLineCount)
 }
 
-func PrintDetails(results []*models.LintResult, format string, detailsFormat bool) {
+// PrintDetails writes the results above the complexity threshold to w,
+// followed by their breakdown in a fenced block if detailsFormat is set
+func PrintDetails(w io.Writer, results []*models.LintResult, format string, detailsFormat bool) {
 	for _, result := range results {
+		if result.Complexity == nil {
+			continue
+		}
+
 		switch format {
 		case "json":
 			// JSON format printing logic
 		case "line-number", "complexity":
-			fmt.Printf("%s:%d:1 - %s has complexity: %s\n", result.File, result.Line, result.Function, result.Message)
+			fmt.Fprintf(w, "%s:%d:1 - %s: %s has complexity: %d\n", result.File, result.Line, result.Severity, result.Function, result.Complexity.Score)
 		default:
-			fmt.Printf("%s:%d:1 - %s has complexity: %s\n", result.File, result.Line, result.Function, result.Message)
+			fmt.Fprintf(w, "%s:%d:1 - %s: %s has complexity: %d\n", result.File, result.Line, result.Severity, result.Function, result.Complexity.Score)
 		}
 
 		if detailsFormat {
 			details := complexity.GetDetail(result)
-			fmt.Println("```go")
-			fmt.Println(details)
-			fmt.Println("```")
+			fmt.Fprintln(w, "```go")
+			fmt.Fprint(w, details)
+			fmt.Fprintln(w, "```")
 		}
 	}
 }
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"github.com/MikeMwita/go-strict/models"
+	"io"
 	"testing"
 )
 
@@ -19,7 +20,7 @@ func TestPrintDetails(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			PrintDetails(tt.args.results, tt.args.format, tt.args.detailsFormat)
+			PrintDetails(io.Discard, tt.args.results, tt.args.format, tt.args.detailsFormat)
 		})
 	}
 }